@@ -3,9 +3,11 @@ package clap
 import (
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -14,12 +16,151 @@ var (
 	ErrIgnoredArgument      = errors.New("ignored argument")
 	ErrMandatoryArgument    = errors.New("mandatory argument")
 	ErrDuplicatedArgument   = errors.New("duplicated argument")
+	ErrHelp                 = errors.New("help requested")
+	ErrUnknownCommand       = errors.New("unknown command")
+	ErrUnknownArgument      = errors.New("unknown argument")
+	ErrInvalidChoice        = errors.New("invalid choice")
+	ErrInvalidValue         = errors.New("invalid value")
 )
 
-func consumeArguments(start int, args []string, count int) (int, []string) {
+const endOfOptions string = "--"
+
+// errorList collects the errors found while parsing. Unless all the errors
+// are requested, parsing stops at the first one
+type errorList struct {
+	all  bool
+	errs []error
+}
+
+// add adds err to the list, and returns true if parsing must stop
+func (e *errorList) add(err error) bool {
+	e.errs = append(e.errs, err)
+	return !e.all
+}
+
+// err returns nil if there are no errors, the error if there is only one,
+// or all the errors joined together
+func (e *errorList) err() error {
+	if len(e.errs) == 1 {
+		return e.errs[0]
+	}
+	return errors.Join(e.errs...)
+}
+
+// trailingArguments returns the given arguments without the first end-of-options
+// marker, since everything following it is taken verbatim
+func trailingArguments(args []string) []string {
 	var values []string
+	for i, arg := range args {
+		if arg == endOfOptions {
+			return append(values, args[i+1:]...)
+		}
+		values = append(values, arg)
+	}
+	return values
+}
+
+// trailingField returns the description of the trailing field, if the struct
+// has one that can be set
+func trailingField(fieldDescs map[string]*fieldDescription, reflectValue reflect.Value) (*fieldDescription, bool) {
+	desc, ok := fieldDescs[trailing]
+	if !ok || !reflectValue.FieldByIndex(desc.Field).CanSet() {
+		return nil, false
+	}
+	return desc, true
+}
+
+// endOfOptionsToTrailing gives all the arguments following the end-of-options
+// marker to the trailing field
+func endOfOptionsToTrailing(args []string, fieldDescs map[string]*fieldDescription, reflectValue reflect.Value) error {
+	if len(args) == 0 {
+		return nil
+	}
+	desc, ok := trailingField(fieldDescs, reflectValue)
+	if !ok {
+		return &ParseError{
+			Kind: ErrUnexpectedArgument, Name: endOfOptions, Value: strings.Join(args, " "),
+			Type: "a trailing field",
+		}
+	}
+	desc.Args = append(desc.Args, args...)
+	return nil
+}
+
+// isInteger returns true for signed and unsigned integer kinds
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// isSigned returns true for signed integer kinds
+func isSigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isNumeric returns true for integer and float kinds
+func isNumeric(kind reflect.Kind) bool {
+	return isInteger(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// elemKind returns the kind of the elements of slices and arrays, or
+// reflect.Invalid for other types
+func elemKind(t reflect.Type) reflect.Kind {
+	if kind := kindOf(t); kind == reflect.Slice || kind == reflect.Array {
+		return kindOf(t.Elem())
+	}
+	return reflect.Invalid
+}
+
+// isValue returns true if arg is a value for the given field rather than a flag.
+// Negative numbers are values for numeric and duration fields, unless a flag with the same
+// name exists
+func isValue(arg string, desc *fieldDescription, fieldDescs map[string]*fieldDescription) bool {
+	if !strings.HasPrefix(arg, "-") {
+		return true
+	}
+	duration := hasType(desc.Type, durationType)
+	if !duration && !isNumeric(kindOf(desc.Type)) && !isNumeric(elemKind(desc.Type)) {
+		return false
+	}
+	if len(arg) < 2 || (arg[1] != '.' && (arg[1] < '0' || arg[1] > '9')) {
+		return false
+	}
+	if duration {
+		if _, err := time.ParseDuration(arg); err != nil {
+			return false
+		}
+	} else if _, err := strconv.ParseFloat(arg, 64); err != nil {
+		if _, err := strconv.ParseInt(arg, 0, 64); err != nil {
+			return false
+		}
+	}
+	_, ok := fieldDescs[arg[:2]]
+	return !ok
+}
+
+// consumeArguments returns the values of a slice, an array or a map, starting
+// at start and stopping before count, at the first flag or at the first command
+func consumeArguments(start int, args []string, count int, desc *fieldDescription,
+	fieldDescs map[string]*fieldDescription,
+) (int, []string) {
+	var values []string
+	if count > len(args) {
+		count = len(args)
+	}
 	for ; start < count; start++ {
-		if strings.HasPrefix(args[start], "-") {
+		if !isValue(args[start], desc, fieldDescs) {
+			break
+		}
+		if other, ok := fieldDescs[args[start]]; ok && other.Command != "" {
 			break
 		}
 		values = append(values, args[start])
@@ -27,172 +168,420 @@ func consumeArguments(start int, args []string, count int) (int, []string) {
 	return start - 1, values
 }
 
-func stringsToInts(strs []string) ([]int, error) {
-	var ints []int
-	for _, str := range strs {
-		var val int64
-		var err error
-		if val, err = strconv.ParseInt(str, 10, 64); err != nil {
-			return nil, fmt.Errorf("%w (got '%s', expected integer)", err, str)
+// splitArgument splits arguments like --name=value or -n=value on the first '='
+// and returns the name, the value and whether a value was attached
+func splitArgument(arg string) (string, string, bool) {
+	if !strings.HasPrefix(arg, "-") {
+		return arg, "", false
+	}
+	return strings.Cut(arg, "=")
+}
+
+// isShortFlags returns true if each char of str is a known short flag
+func isShortFlags(str string, fieldDescs map[string]*fieldDescription) bool {
+	for k := 0; k < len(str); k++ {
+		if _, ok := fieldDescs["-"+str[k:k+1]]; !ok {
+			return false
 		}
-		ints = append(ints, int(val))
 	}
-	return ints, nil
+	return true
 }
 
-func argsToFields(args []string, fieldDescs map[string]*fieldDescription, cfg any) (*Results, error) {
+// splitShortFlags expands clustered short flags like -abc into -a, -b and -c.
+// Only the last flag of the cluster may be a non-boolean flag, and it can have
+// its value attached, like in -p8080 or -p=8080. As with getopt, whatever
+// follows a non-boolean flag is its value, unless it is only made of flags
+func splitShortFlags(arg string, fieldDescs map[string]*fieldDescription) ([]string, string, bool, error) {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return nil, "", false, nil
+	}
+	var flags []string
+	for k := 1; k < len(arg); k++ {
+		flag := "-" + arg[k:k+1]
+		desc, ok := fieldDescs[flag]
+		if !ok {
+			return nil, "", false, nil
+		}
+		flags = append(flags, flag)
+		rest := arg[k+1:]
+		if strings.HasPrefix(rest, "=") {
+			return flags, rest[1:], true, nil
+		}
+		if kindOf(desc.Type) == reflect.Bool || desc.Count {
+			continue
+		}
+		if rest == "" {
+			return flags, "", false, nil
+		}
+		if isShortFlags(rest, fieldDescs) {
+			return nil, "", false, &ParseError{
+				Kind: ErrUnexpectedArgument, Name: flag, Field: desc.FieldName, Value: arg,
+				details: fmt.Sprintf("non-boolean flag '%s' must be last in '%s'", flag, arg),
+			}
+		}
+		return flags, rest, true, nil
+	}
+	return flags, "", false, nil
+}
+
+// argToField stores the value(s) of the argument in the field description.
+// A repeated argument is handled according to the repeat policy of the field:
+// its values replace or are appended to the previous ones, or they are
+// consumed, but not stored
+func argToField(i int, args []string, arg, value string, attached bool, desc *fieldDescription,
+	fieldDescs map[string]*fieldDescription, results *Results, reflectValue reflect.Value,
+) (int, error) {
+	if desc.Count {
+		return i, countToField(arg, value, attached, desc, results)
+	}
+	if desc.Found {
+		switch desc.repeatPolicy() {
+		case repeatLast:
+			desc.Args = nil
+		case repeatAppend:
+		default:
+			duplicate := *desc
+			duplicate.Found, duplicate.Args = false, nil
+			var err error
+			i, err = argToField(i, args, arg, value, attached, &duplicate, fieldDescs, results, reflectValue)
+			if desc.repeatPolicy() == repeatFirst {
+				return i, err
+			}
+			// the errors of the duplicate, like a missing value, are kept along with the duplication
+			results.Duplicated = append(results.Duplicated, arg)
+			return i, &ParseError{Kind: ErrDuplicatedArgument, Name: arg, Field: desc.FieldName, Err: err}
+		}
+	}
+	desc.Found = true
+	field := reflectValue.FieldByIndex(desc.Field)
+	if !field.CanSet() {
+		return i, nil
+	}
+	switch kindOf(desc.Type) {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fallthrough
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fallthrough
+	case reflect.String, reflect.Float32, reflect.Float64:
+		if !attached {
+			i++
+			if i >= len(args) || !isValue(args[i], desc, fieldDescs) {
+				results.Missing = append(results.Missing, arg)
+				return i - 1, &ParseError{Kind: ErrMissingArgumentValue, Name: arg, Field: desc.FieldName}
+			}
+			value = args[i]
+		}
+		desc.Args = append(desc.Args, value)
+	case reflect.Bool:
+		val := true
+		if attached {
+			var err error
+			if val, err = strconv.ParseBool(value); err != nil {
+				results.Unexpected = append(results.Unexpected, arg)
+				return i, &ParseError{
+					Kind: ErrUnexpectedArgument, Name: arg, Field: desc.FieldName, Value: value, Type: "boolean",
+					Err: err,
+				}
+			}
+		}
+		if arg == "--no-"+desc.LongName {
+			val = !val
+		}
+		desc.Args = append(desc.Args, strconv.FormatBool(val))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		var values []string
+		if attached {
+			if value != "" {
+				values = strings.Split(value, ",")
+			}
+			if desc.Type.Kind() == reflect.Array && len(values) > desc.Type.Len() {
+				results.Unexpected = append(results.Unexpected, arg)
+				return i, &ParseError{
+					Kind: ErrUnexpectedArgument, Name: arg, Field: desc.FieldName, Value: value,
+					details: fmt.Sprintf("got %d values, expected at most %d", len(values), desc.Type.Len()),
+				}
+			}
+		} else {
+			count := len(args)
+			if desc.Type.Kind() == reflect.Array {
+				count = i + 1 + desc.Type.Len()
+			}
+			i, values = consumeArguments(i+1, args, count, desc, fieldDescs)
+		}
+		if len(values) == 0 {
+			results.Missing = append(results.Missing, arg)
+			return i, &ParseError{Kind: ErrMissingArgumentValue, Name: arg, Field: desc.FieldName}
+		}
+		desc.Args = append(desc.Args, values...)
+	}
+	return i, nil
+}
+
+// countToField increments the value of a counting field, or decrements it
+// when arg is one of its decrement names. Counting starts at zero
+func countToField(arg, value string, attached bool, desc *fieldDescription, results *Results) error {
+	if attached {
+		results.Unexpected = append(results.Unexpected, arg)
+		return &ParseError{
+			Kind: ErrUnexpectedArgument, Name: arg, Field: desc.FieldName, Value: value,
+			details: fmt.Sprintf("counting flag '%s' takes no value", arg),
+		}
+	}
+	var total int
+	if desc.Found {
+		total, _ = strconv.Atoi(desc.Args[0])
+	}
+	delta := 1
+	for _, name := range desc.Decrement {
+		if arg == name {
+			delta = -1
+		}
+	}
+	desc.Found = true
+	desc.Args = []string{strconv.Itoa(total + delta)}
+	return nil
+}
+
+// unknownArgument returns the error of an argument which is not recognized,
+// suggesting the closest known parameter, if any
+func unknownArgument(arg string, names []string) *ParseError {
+	err := &ParseError{Kind: ErrUnknownArgument, Name: arg, Suggestions: names}
+	if len(names) != 0 {
+		err.details = fmt.Sprintf("did you mean '%s'?", names[0])
+	}
+	return err
+}
+
+// envToField fills the field from its environment variable, if any, when
+// the field is not present on the command line. An empty variable is unset
+func envToField(desc *fieldDescription, opts *options) error {
+	value := os.Getenv(desc.Env)
+	if value == "" {
+		return nil
+	}
+	switch kindOf(desc.Type) {
+	case reflect.Bool:
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fromEnv(&ParseError{
+				Kind: ErrUnexpectedArgument, Name: desc.name(), Field: desc.FieldName, Value: value, Type: "boolean",
+				Err: err,
+			}, desc.Env)
+		}
+		desc.Args = []string{strconv.FormatBool(val)}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		values := strings.Split(value, opts.envSeparator)
+		if desc.Type.Kind() == reflect.Array && len(values) > desc.Type.Len() {
+			return &ParseError{
+				Kind: ErrUnexpectedArgument, Name: desc.name(), Field: desc.FieldName, Value: value,
+				details: fmt.Sprintf("got %d values from environment variable '%s', expected at most %d", len(values),
+					desc.Env, desc.Type.Len()),
+			}
+		}
+		desc.Args = values
+	default:
+		desc.Args = []string{value}
+	}
+	desc.Found, desc.FromEnv = true, true
+	return nil
+}
+
+// fromEnv names the environment variable the value of err comes from in
+// its details, keeping what was expected
+func fromEnv(err *ParseError, env string) *ParseError {
+	got := fmt.Sprintf("got '%s'", err.Value)
+	source := fmt.Sprintf("%s from environment variable '%s'", got, env)
+	switch {
+	case strings.HasPrefix(err.details, got):
+		err.details = source + strings.TrimPrefix(err.details, got)
+	case err.details != "":
+		err.details += fmt.Sprintf(" in environment variable '%s'", env)
+	case err.expected() != "":
+		err.details = fmt.Sprintf("%s, expected %s", source, err.expected())
+	default:
+		err.details = source
+	}
+	return err
+}
+
+func argsToFields(args []string, fieldDescs map[string]*fieldDescription, cfg any, opts *options,
+	errs *errorList,
+) (*Results, error) {
 	results := &Results{}
 	reflectValue := reflect.ValueOf(cfg).Elem()
 	for i := 0; i < len(args); i++ {
+		var err error
 		arg := args[i]
-		if desc, ok := fieldDescs[arg]; ok {
-			if desc.Found {
-				results.Duplicated = append(results.Duplicated, arg)
-				return results, fmt.Errorf("argument '%s': %w (duplicated argument)", arg, ErrDuplicatedArgument)
+		if arg == endOfOptions {
+			if err = endOfOptionsToTrailing(args[i+1:], fieldDescs, reflectValue); err != nil {
+				results.Unexpected = append(results.Unexpected, arg)
+				if errs.add(err) {
+					return results, errs.err()
+				}
 			}
+			break
+		}
+		name, value, attached := splitArgument(arg)
+		if desc, ok := fieldDescs[name]; ok && desc.Command != "" {
 			desc.Found = true
-			field := reflectValue.Field(desc.Field)
-			if !field.CanSet() {
-				continue
-			}
-			switch desc.Type.Kind() {
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				fallthrough
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				fallthrough
-			case reflect.String, reflect.Float32, reflect.Float64:
-				i++
-				if i >= len(args) || strings.HasPrefix(args[i], "-") {
-					results.Missing = append(results.Missing, arg)
-					return results, fmt.Errorf("argument '%s': %w (missing argument)", arg, ErrMissingArgumentValue)
+			desc.Args = args[i+1:]
+			break
+		}
+		if desc, ok := fieldDescs[name]; ok && strings.HasPrefix(name, "-") {
+			if i, err = argToField(i, args, name, value, attached, desc, fieldDescs, results, reflectValue); err != nil {
+				if errs.add(err) {
+					return results, errs.err()
 				}
-				desc.Args = append(desc.Args, args[i])
-			case reflect.Bool:
-				desc.Args = append(desc.Args, fmt.Sprintf("%v", !strings.HasPrefix(arg, "--no-")))
-			case reflect.Slice, reflect.Array:
-				var values []string
-				count := len(args)
-				if desc.Type.Kind() == reflect.Array {
-					count = i + 1 + desc.Type.Len()
+			}
+			continue
+		}
+		flags, value, attached, err := splitShortFlags(arg, fieldDescs)
+		if err != nil {
+			results.Unexpected = append(results.Unexpected, arg)
+			if errs.add(err) {
+				return results, errs.err()
+			}
+			continue
+		}
+		if len(flags) != 0 {
+			for k, flag := range flags {
+				if k != len(flags)-1 {
+					_, err = argToField(i, args, flag, "", false, fieldDescs[flag], fieldDescs, results, reflectValue)
+				} else {
+					i, err = argToField(i, args, flag, value, attached, fieldDescs[flag], fieldDescs, results, reflectValue)
 				}
-				i, values = consumeArguments(i+1, args, count)
-				if len(values) == 0 {
-					results.Missing = append(results.Missing, arg)
-					return results, fmt.Errorf("argument '%s': %w (missing argument)", arg, ErrMissingArgumentValue)
+				if err != nil && errs.add(err) {
+					return results, errs.err()
 				}
-				desc.Args = append(desc.Args, values...)
 			}
-		} else {
-			found := false
-			for j := i; j < len(args); j++ {
-				if strings.HasPrefix(args[j], "-") {
-					results.Ignored = append(results.Ignored, arg)
-					// does not generate an error
-					found = true
-					break
-				}
+			continue
+		}
+		trailingDesc, hasTrailing := trailingField(fieldDescs, reflectValue)
+		// positional arguments go to the trailing field, if any, rather than being unknown commands
+		if err = unknownCommand(arg, fieldDescs); err != nil && !hasTrailing {
+			results.Unexpected = append(results.Unexpected, arg)
+			if errs.add(err) {
+				return results, errs.err()
 			}
-			if !found {
-				if desc, ok := fieldDescs[trailing]; ok {
-					field := reflectValue.Field(desc.Field)
-					if field.CanSet() {
-						var values []string
-						for j := i; j < len(args); j++ {
-							values = append(values, args[j])
-						}
-						desc.Args = append(desc.Args, values...)
-						break
-					}
+			break
+		}
+		found := false
+		for j := i; j < len(args) && args[j] != endOfOptions; j++ {
+			if strings.HasPrefix(args[j], "-") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			if hasTrailing {
+				trailingDesc.Args = append(trailingDesc.Args, trailingArguments(args[i:])...)
+				break
+			}
+			if !opts.strict {
+				continue
+			}
+		}
+		results.Ignored = append(results.Ignored, arg)
+		names := suggestions(arg, fieldDescs)
+		if len(names) != 0 {
+			if results.Suggestions == nil {
+				results.Suggestions = make(map[string][]string)
+			}
+			results.Suggestions[arg] = names
+		}
+		// only generates an error in strict mode
+		if opts.strict && errs.add(unknownArgument(arg, names)) {
+			return results, errs.err()
+		}
+	}
+	for _, desc := range sortedFieldDescriptions(fieldDescs) {
+		if desc.Found || !reflectValue.FieldByIndex(desc.Field).CanSet() {
+			continue
+		}
+		if desc.Env != "" {
+			if err := envToField(desc, opts); err != nil {
+				results.Unexpected = append(results.Unexpected, desc.name())
+				if errs.add(err) {
+					return results, errs.err()
 				}
 			}
 		}
+		// pre-filled values take precedence over the default value of the tag
+		if !desc.Found && len(desc.Default) != 0 && reflectValue.FieldByIndex(desc.Field).IsZero() {
+			desc.Args = desc.Default
+		}
 	}
-	for _, desc := range fieldDescs {
+	for _, desc := range sortedFieldDescriptions(fieldDescs) {
 		if !desc.Found && desc.Mandatory {
 			name := desc.LongName
 			if name == "" {
 				name = desc.ShortName
 			}
 			results.Mandatory = append(results.Mandatory, name)
+			errs.add(&ParseError{Kind: ErrMandatoryArgument, Name: desc.name(), Field: desc.FieldName, details: "not found"})
 		}
 	}
-	if len(results.Mandatory) != 0 {
-		return results, fmt.Errorf("mandatory argument/s: '%v' not found: %w", strings.Join(results.Mandatory, ","), ErrMandatoryArgument)
-	}
-
-	return results, nil
+	return results, errs.err()
 }
 
-func fillStruct(args []string, fieldDescs map[string]*fieldDescription, cfg any) (*Results, error) {
-	results, err := argsToFields(args, fieldDescs, cfg)
-	if err != nil {
+func fillStruct(args []string, fieldDescs map[string]*fieldDescription, cfg any, opts *options) (*Results, error) {
+	errs := &errorList{all: opts.allErrors}
+	results, err := argsToFields(args, fieldDescs, cfg, opts, errs)
+	if err != nil && !errs.all {
 		return results, err
 	}
+	var commandDesc *fieldDescription
 	reflectValue := reflect.ValueOf(cfg).Elem()
-	for name, desc := range fieldDescs {
-		field := reflectValue.Field(desc.Field)
+	for _, desc := range sortedFieldDescriptions(fieldDescs) {
+		if desc.Command != "" {
+			if desc.Found {
+				commandDesc = desc
+			}
+			continue
+		}
+		field := reflectValue.FieldByIndex(desc.Field)
 		if !field.CanSet() || len(desc.Args) == 0 || desc.Visited {
 			continue
 		}
 		desc.Visited = true
-		switch desc.Type.Kind() {
-		case reflect.String:
-			field.SetString(desc.Args[0])
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			val, err := strconv.ParseInt(desc.Args[0], 10, 64)
-			if err != nil {
-				results.Unexpected = append(results.Unexpected, name)
-				return results, fmt.Errorf("argument '%s': %w (got '%s', expected integer)", name,
-					ErrUnexpectedArgument, desc.Args[0])
-			}
-			field.SetInt(val)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			val, err := strconv.ParseInt(desc.Args[0], 10, 64)
-			if err != nil {
-				results.Unexpected = append(results.Unexpected, name)
-				return results, fmt.Errorf("argument '%s': %w (got '%s', expected integer)", name,
-					ErrUnexpectedArgument, desc.Args[0])
-			}
-			field.SetUint(uint64(val))
-		case reflect.Float32, reflect.Float64:
-			val, err := strconv.ParseFloat(desc.Args[0], 64)
-			if err != nil {
-				results.Unexpected = append(results.Unexpected, name)
-				return results, fmt.Errorf("argument '%s': %w (got '%s', expected float)", name,
-					ErrUnexpectedArgument, desc.Args[0])
-			}
-			field.SetFloat(val)
-		case reflect.Bool:
-			field.SetBool(desc.Args[0] == "true")
-		case reflect.Slice:
-			if desc.Type.Elem().Kind() == reflect.String {
-				field.Set(reflect.ValueOf(desc.Args))
-			} else if desc.Type.Elem().Kind() == reflect.Int {
-				ints, err := stringsToInts(desc.Args)
-				if err != nil {
-					results.Unexpected = append(results.Unexpected, name)
-					return results, fmt.Errorf("argument '%s': %w", name, err)
-				}
-				field.Set(reflect.ValueOf(ints))
-			}
-		case reflect.Array:
-			if desc.Type.Elem().Kind() == reflect.String {
-				arrayType := reflect.ArrayOf(desc.Type.Len(), desc.Type.Elem())
-				v := reflect.New(arrayType).Elem()
-				reflect.Copy(v, reflect.ValueOf(desc.Args))
-				field.Set(v)
-			} else if desc.Type.Elem().Kind() == reflect.Int {
-				ints, err := stringsToInts(desc.Args)
-				if err != nil {
-					results.Unexpected = append(results.Unexpected, name)
-					return results, fmt.Errorf("argument '%s': %w", name, err)
-				}
-				arrayType := reflect.ArrayOf(desc.Type.Len(), desc.Type.Elem())
-				v := reflect.New(arrayType).Elem()
-				reflect.Copy(v, reflect.ValueOf(ints))
-				field.Set(v)
+		value := reflect.New(desc.Type).Elem()
+		args, err := checkChoices(desc.Args, desc)
+		if err == nil {
+			err = setValue(value, args, desc)
+		}
+		if err == nil {
+			err = checkConstraints(value, len(args), desc)
+		}
+		if err != nil {
+			err.Name, err.Field = desc.name(), desc.FieldName
+			if desc.FromEnv {
+				err = fromEnv(err, desc.Env)
+			}
+			switch err.Kind {
+			case ErrDuplicatedArgument:
+				results.Duplicated = append(results.Duplicated, err.Name)
+			case ErrInvalidChoice:
+				results.InvalidChoices = append(results.InvalidChoices, err.Name)
+			case ErrInvalidValue:
+				results.Invalid = append(results.Invalid, err.Name)
+			default:
+				results.Unexpected = append(results.Unexpected, err.Name)
+			}
+			if errs.add(err) {
+				return results, errs.err()
+			}
+		} else if desc.Pointer {
+			field.Set(value.Addr())
+		} else {
+			field.Set(value)
+		}
+	}
+	if commandDesc != nil {
+		if field := reflectValue.FieldByIndex(commandDesc.Field); field.CanSet() {
+			if err := commandToField(commandDesc, field, results, opts); err != nil {
+				errs.add(err)
 			}
 		}
 	}
-	return results, nil
+	return results, errs.err()
 }
@@ -6,7 +6,7 @@ import "reflect"
 Parses the command line arguments into the given struct
 Struct tag looks like one of the following:
 
-	`clap:"longname[, shortname][, optional]"`
+	`clap:"longname[, shortname][, mandatory][, options...]"`
 
 longname: represents the long name of the command line
 parameter, without the --. For instance:
@@ -25,29 +25,124 @@ Examples:
 	`clap:"recursive,,optional"`
 	`clap:"recursive,R,optional"`
 
+options are key=value pairs, for instance env=NAME to fill the
+field from the NAME environment variable when the parameter is not
+on the command line. Slices and arrays are split on "," or on the
+separator given by WithEnvSeparator:
+
+	`clap:"port,p,env=APP_PORT"`
+
+Values containing commas are enclosed in single quotes, a quote being
+doubled inside them:
+
+	`clap:"since,layout='Mon, 02 Jan 2006'"`
+
+The default=VALUE option gives the value used when the parameter is
+neither on the command line nor in the environment, and the field is
+not pre-filled. It cannot be used with mandatory. Values of slices
+and arrays are separated by |:
+
+	`clap:"sizes,default=1|2|3"`
+
+The repeat=POLICY option tells what to do when the parameter is
+repeated: error (ErrDuplicatedArgument, the default), last or first
+to keep the values of the last or first occurrence, or append (slices
+and maps only) to accumulate the values. Maps append by default:
+
+	`clap:"include,I,repeat=append"`
+
+The count option turns an integer field into a counting flag, which
+is incremented each time it appears, including in clustered short
+flags like -vvv. The decrement=NAMES option gives the names, separated
+by |, of the flags decrementing it, for signed integers only:
+
+	`clap:"verbose,v,count,decrement=quiet|q"`
+
+The choices=VALUES option restricts the values of strings, or of
+slices and arrays of strings, to the given values, separated by |.
+Other values return ErrInvalidChoice. The ignorecase option accepts
+the values regardless of their case, storing them as spelled in the
+choices:
+
+	`clap:"format,choices=json|yaml|text,ignorecase"`
+
+The min=VALUE and max=VALUE options bound numbers and durations, the
+minlen=N and maxlen=N options bound the number of characters of
+strings and the number of values of slices and maps, and the
+pattern=REGEXP option requires strings to match a regular expression.
+The values of slices, arrays and maps are checked one by one, and
+invalid values return ErrInvalidValue:
+
+	`clap:"port,min=1,max=65535"`
+
+Embedded structs without a tag contribute their parameters directly,
+and struct fields tagged with the prefix option contribute parameters
+whose long names are prefixed, like --db-host here. Names must be
+unique across all the structs, otherwise ErrConflictingTag is returned:
+
+	`clap:"db,prefix"`
+
 There is a special longname that you can use to retrieve
 all trailing parameters on your command line: trailing.
 It is used like this:
 
 	`clap:"trailing"`
 
+All the parameters following a -- on the command line are given
+verbatim to the trailing field, even if they start with a -.
+
+A description of each parameter, used by Usage, can be given with
+the help struct tag:
+
+	`clap:"recursive,R" help:"recurse into subdirectories"`
+
+Commands are declared as struct fields, using the command keyword,
+and can be nested at will:
+
+	`clap:"command=run"`
+
+The parameters preceding the command on the command line fill the
+parent struct, and the following ones fill the command struct. The
+selected command path is given by Results.Commands.
+
+Options can be given to change the behavior of Parse, for instance
+WithHelp to return ErrHelp when -h or --help is on the command line,
+WithAllErrors to report all the errors instead of the first one, or
+WithStrict to reject unknown parameters.
+
 Supported field types:
 
-	int
-	bool
 	string
-	[]int
-	[]string
+	bool
+	int, int8, int16, int32, int64
+	uint, uint8, uint16, uint32, uint64
+	float32, float64
+	time.Duration, parsed with time.ParseDuration
+	time.Time, parsed with the layout=LAYOUT option (time.RFC3339 by default)
+	types implementing Value or encoding.TextUnmarshaler
+	slices and arrays of the above
+	maps with keys and values of the above, given as key=value
+	pointers to any of the above, allocated only when a value is given
+
+Fields of any other type return ErrInvalidTag.
 */
-func Parse[T any](args []string, cfg *T) (*Results, error) {
+func Parse[T any](args []string, cfg *T, opts ...Option) (*Results, error) {
+	return parse(args, cfg, newOptions(opts))
+}
+
+// parse parses the arguments into cfg, which is a pointer to a struct
+func parse(args []string, cfg any, opts *options) (*Results, error) {
 	var err error
 	var results *Results
 	var fieldDescs map[string]*fieldDescription
-	fieldDescs, err = computeFieldDescriptions(reflect.TypeOf(*cfg))
+	fieldDescs, err = computeFieldDescriptions(reflect.TypeOf(cfg).Elem())
 	if err != nil {
 		return nil, err
 	}
-	if results, err = fillStruct(args, fieldDescs, cfg); err != nil {
+	if opts.help && isHelpRequested(args, fieldDescs) {
+		return &Results{}, ErrHelp
+	}
+	if results, err = fillStruct(args, fieldDescs, cfg, opts); err != nil {
 		return results, err
 	}
 	return results, nil
@@ -3,6 +3,7 @@ package clap_test
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/fred1268/go-clap/clap"
@@ -309,7 +310,7 @@ func TestTypes(t *testing.T) {
 	var results *clap.Results
 	if results, err = clap.Parse([]string{
 		"--string", "str", "--int", "10", "--int8", "8", "--int16", "16", "--int32", "32", "--int64", "64",
-		"--uint", "12", "--uint8", "65535", "--uint16", "65535", "--uint32", "65535", "--uint64", "65535",
+		"--uint", "12", "--uint8", "255", "--uint16", "65535", "--uint32", "65535", "--uint64", "65535",
 		"--float32", "12.32", "--float64", "12.64", "--bool", "--no-defaulttrue", "--string-slice", "a", "b", "c",
 		"--int-slice", "10", "11", "12", "--string-array", "a", "b", "--int-array", "10", "11", "12",
 		"w", "x", "y", "z",
@@ -423,6 +424,103 @@ func TestDuplicatedArgument(t *testing.T) {
 	t.Logf("t: %v\n", results)
 }
 
+func TestRepeatedArgument(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Includes []string          `clap:"--include,-I,repeat=append"`
+		Output   string            `clap:"--output,-o,repeat=last"`
+		Config   string            `clap:"--config,repeat=first"`
+		Verbose  bool              `clap:"--verbose,-v,repeat=last"`
+		Sizes    []int             `clap:"--size,repeat=last"`
+		Strict   string            `clap:"--strict,repeat=error"`
+		Labels   map[string]string `clap:"--label"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--include", "a", "b", "-I", "c", "-o", "out.txt", "--output=final.txt", "--config", "first.yml",
+		"--config", "second.yml", "-v", "--no-verbose", "--size", "1", "2", "--size", "3",
+		"--label", "a=1", "--label", "b=2",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Includes: []string{"a", "b", "c"}, Output: "final.txt", Config: "first.yml", Sizes: []int{3},
+		Labels: map[string]string{"a": "1", "b": "2"},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if results.HasWarnings() {
+		t.Errorf("unexpected warnings: %v", results)
+	}
+	if results, err = clap.Parse([]string{"--strict", "a", "--strict", "b"}, cfg); !errors.Is(err, clap.ErrDuplicatedArgument) {
+		t.Errorf("unexpected repeated argument: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Verbosity int      `clap:"--verbose,-v,count,decrement=quiet|q"`
+		Debug     uint8    `clap:",-d,count"`
+		Recursive bool     `clap:"--recursive,-r"`
+		Port      int      `clap:"--port,-p"`
+		Files     []string `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-vvv", "-rvp", "8080", "--verbose", "-q", "-dd", "file.txt"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Verbosity: 4, Debug: 2, Recursive: true, Port: 8080, Files: []string{"file.txt"}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"-qq", "--quiet"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if cfg.Verbosity != -3 {
+		t.Errorf("wanted: '-3', got '%v'", cfg.Verbosity)
+	}
+	if results, err = clap.Parse([]string{"--verbose=2"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected counting value: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestRepeatedArgumentMissingValue(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		First  string `clap:"--first,repeat=first"`
+		Strict string `clap:"--strict"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--first", "1", "--first"}, cfg); !errors.Is(err, clap.ErrMissingArgumentValue) {
+		t.Errorf("unexpected missing value: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.Missing, []string{"--first"}) {
+		t.Errorf("wanted: '[--first]', got '%v'", results.Missing)
+	}
+	if results, err = clap.Parse([]string{"--strict", "1", "--strict"}, cfg); !errors.Is(err, clap.ErrDuplicatedArgument) {
+		t.Errorf("unexpected duplicated argument: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.Missing, []string{"--strict"}) {
+		t.Errorf("wanted: '[--strict]', got '%v'", results.Missing)
+	}
+}
+
 func TestMissingArgument(t *testing.T) {
 	t.Parallel()
 	type config struct {
@@ -498,3 +596,507 @@ func TestUnsettableField(t *testing.T) {
 	cfg.aString = "" // use field
 	t.Logf("t: %v\n", results)
 }
+
+func TestAttachedValues(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port        int       `clap:"--port,-p"`
+		Name        string    `clap:"--name,-n"`
+		Ratio       float64   `clap:"--ratio"`
+		Secure      bool      `clap:"--secure"`
+		Verbose     bool      `clap:"--verbose"`
+		Cache       bool      `clap:"--cache"`
+		Extensions  []string  `clap:"--extensions"`
+		Ports       []int     `clap:"--ports"`
+		Origins     [2]string `clap:"--origins"`
+		ConfigFiles []string  `clap:"trailing"`
+	}
+	cfg := &config{Secure: true, Cache: true}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--port=8080", "-n=clap", "--ratio=1.5", "--secure=false", "--verbose=true", "--no-cache=false",
+		"--extensions=jpg,png", "--ports=80,443", "--origins=a,b", "config.json",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Port: 8080, Name: "clap", Ratio: 1.5, Secure: false, Verbose: true, Cache: true,
+		Extensions: []string{"jpg", "png"}, Ports: []int{80, 443}, Origins: [2]string{"a", "b"},
+		ConfigFiles: []string{"config.json"},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidAttachedValues(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Secure  bool      `clap:"--secure"`
+		Origins [2]string `clap:"--origins"`
+		Ports   []int     `clap:"--ports"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--secure=maybe"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected boolean parsing: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{"--origins=a,b,c"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected array parsing: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{"--ports="}, cfg); !errors.Is(err, clap.ErrMissingArgumentValue) {
+		t.Errorf("unexpected slice parsing: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestShortFlagsCluster(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Recursive bool     `clap:"--recursive,-r"`
+		Force     bool     `clap:"--force,-f"`
+		Verbose   bool     `clap:"--verbose,-v"`
+		Port      int      `clap:"--port,-p"`
+		Name      string   `clap:",-n"`
+		Tags      []string `clap:",-t"`
+		All       bool     `clap:",-a"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-rfp8080", "-nalice", "-vt", "a", "b"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Recursive: true, Force: true, Verbose: true, Port: 8080, Name: "alice", Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"-rp", "8080", "-fv=false", "-n=clap"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted = &config{Recursive: true, Force: true, Port: 8080, Name: "clap"}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidShortFlagsCluster(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Recursive bool `clap:"--recursive,-r"`
+		Port      int  `clap:"--port,-p"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-pr", "8080"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected cluster parsing: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"-rx", "-p", "8080"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if cfg.Recursive || len(results.Ignored) != 1 || results.Ignored[0] != "-rx" {
+		t.Errorf("unexpected partial cluster: %v", results.Ignored)
+	}
+}
+
+func TestEndOfOptions(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Verbose bool     `clap:"--verbose,-v"`
+		Size    int      `clap:"--size,-s"`
+		Files   []string `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-v", "file.txt", "--", "-weird.txt", "--size", "--"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Verbose: true, Files: []string{"file.txt", "-weird.txt", "--size", "--"}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"--size", "10", "--"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted = &config{Size: 10}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestEndOfOptionsWithoutTrailing(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Verbose bool `clap:"--verbose,-v"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-v", "--", "-weird.txt"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected end of options: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestNegativeNumbers(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Offset int      `clap:"--offset,-o"`
+		Scale  float64  `clap:"--scale"`
+		Deltas []int    `clap:"--deltas"`
+		Point  [2]int   `clap:"--point"`
+		Name   string   `clap:"--name"`
+		Nine   bool     `clap:",-9"`
+		Files  []string `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--offset", "-5", "--scale", "-.5", "--point", "-1", "2", "--deltas", "1", "-2", "3", "-9", "file.txt",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Offset: -5, Scale: -.5, Deltas: []int{1, -2, 3}, Point: [2]int{-1, 2}, Nine: true,
+		Files: []string{"file.txt"},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"--name", "-1"}, cfg); !errors.Is(err, clap.ErrMissingArgumentValue) {
+		t.Errorf("unexpected negative number for string: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("CLAP_PORT", "8080")
+	t.Setenv("CLAP_HOST", "localhost")
+	t.Setenv("CLAP_SECURE", "false")
+	t.Setenv("CLAP_ORIGINS", "a;b")
+	t.Setenv("CLAP_SIZES", "1;2;3")
+	type config struct {
+		Port    int       `clap:"--port,-p,mandatory,env=CLAP_PORT"`
+		Host    string    `clap:"--host,env=CLAP_HOST"`
+		Secure  bool      `clap:"--secure,env=CLAP_SECURE"`
+		Origins [2]string `clap:",-o,env=CLAP_ORIGINS"`
+		Sizes   []int     `clap:"--sizes,env=CLAP_SIZES"`
+		Name    string    `clap:"--name,env=CLAP_UNDEFINED"`
+	}
+	cfg := &config{Secure: true, Name: "clap"}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--host", "example.com"}, cfg, clap.WithEnvSeparator(";")); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Port: 8080, Host: "example.com", Secure: false, Origins: [2]string{"a", "b"}, Sizes: []int{1, 2, 3}, Name: "clap",
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidEnv(t *testing.T) {
+	t.Setenv("CLAP_SECURE", "maybe")
+	type config struct {
+		Secure bool `clap:"--secure,env=CLAP_SECURE"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected environment variable: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.Unexpected, []string{"--secure"}) {
+		t.Errorf("wanted: '[--secure]', got '%v'", results.Unexpected)
+	}
+}
+
+func TestInvalidEnvMessages(t *testing.T) {
+	t.Setenv("CLAP_SECURE", "maybe")
+	t.Setenv("CLAP_PORT", "abc")
+	t.Setenv("CLAP_LEVEL", "300")
+	t.Setenv("CLAP_FORMAT", "xml")
+	type config struct {
+		Secure bool   `clap:"--secure,env=CLAP_SECURE"`
+		Port   int    `clap:"--port,env=CLAP_PORT"`
+		Level  int8   `clap:"--level,env=CLAP_LEVEL"`
+		Format string `clap:"--format,env=CLAP_FORMAT,choices=json|yaml"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg, clap.WithAllErrors()); err == nil {
+		t.Errorf("unexpected valid environment variables")
+	}
+	t.Logf("t: %v\n", results)
+	for _, message := range []string{
+		"argument '--secure': unexpected argument (got 'maybe' from environment variable 'CLAP_SECURE', expected boolean)",
+		"argument '--port': unexpected argument (got 'abc' from environment variable 'CLAP_PORT', expected integer)",
+		"argument '--level': unexpected argument (got '300' from environment variable 'CLAP_LEVEL', " +
+			"expected integer between -128 and 127)",
+		"argument '--format': invalid choice (got 'xml' from environment variable 'CLAP_FORMAT', " +
+			"expected one of json, yaml)",
+	} {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("wanted: '%s', got '%s'", message, err)
+		}
+	}
+}
+
+func TestEmptyEnv(t *testing.T) {
+	t.Setenv("CLAP_SECURE", "")
+	t.Setenv("CLAP_PORT", "")
+	type config struct {
+		Secure bool `clap:"--secure,env=CLAP_SECURE"`
+		Port   int  `clap:"--port,env=CLAP_PORT,default=8080"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Port: 8080}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port    int       `clap:"--port,-p,default=8080"`
+		Host    string    `clap:"--host,default=localhost"`
+		Secure  bool      `clap:"--secure,default=true"`
+		Ratio   float64   `clap:"--ratio,default=1.5"`
+		Origins [2]string `clap:",-o,default=a|b"`
+		Sizes   []int     `clap:"--sizes,default=1|2|3"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--host", "example.com", "--sizes", "4"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Port: 8080, Host: "example.com", Secure: true, Ratio: 1.5, Origins: [2]string{"a", "b"}, Sizes: []int{4},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"--no-secure"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if cfg.Secure {
+		t.Errorf("wanted: 'false', got '%v'", cfg.Secure)
+	}
+}
+
+func TestPrefilledDefaults(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Host string `clap:"--host,default=localhost"`
+		Port int    `clap:"--port,-p,default=8080"`
+	}
+	cfg := &config{Host: "example.com"}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Host: "example.com", Port: 8080}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestAllErrors(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		String  string  `clap:"--string"`
+		Int     int     `clap:"--int,-i"`
+		Uint    uint    `clap:"--uint"`
+		Float   float64 `clap:"--float"`
+		Image   string  `clap:"--image,mandatory"`
+		Verbose bool    `clap:"--verbose,-v"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--string", "hello", "--string", "world", "--int", "foo", "--float", "--uint", "bar", "-v",
+	}, cfg, clap.WithAllErrors()); err == nil {
+		t.Errorf("unexpected parsing success")
+	}
+	t.Logf("t: %v\n", results)
+	for _, wanted := range []error{
+		clap.ErrDuplicatedArgument, clap.ErrMissingArgumentValue, clap.ErrMandatoryArgument, clap.ErrUnexpectedArgument,
+	} {
+		if !errors.Is(err, wanted) {
+			t.Errorf("wanted: '%v', got '%v'", wanted, err)
+		}
+	}
+	if !reflect.DeepEqual(results.Duplicated, []string{"--string"}) ||
+		!reflect.DeepEqual(results.Missing, []string{"--float"}) ||
+		!reflect.DeepEqual(results.Mandatory, []string{"image"}) ||
+		!reflect.DeepEqual(results.Unexpected, []string{"--int", "--uint"}) {
+		t.Errorf("unexpected results: %v", results)
+	}
+	if cfg.String != "hello" || !cfg.Verbose {
+		t.Errorf("unexpected config: %v", cfg)
+	}
+}
+
+func TestIntegerRanges(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Int8   int8   `clap:"--int8"`
+		Int64  int64  `clap:"--int64"`
+		UInt16 uint16 `clap:"--uint16"`
+		UInt64 uint64 `clap:"--uint64"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--int8", "-128", "--int64", "-9223372036854775808", "--uint16", "65535", "--uint64", "18446744073709551615",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Int8: -128, Int64: -9223372036854775808, UInt16: 65535, UInt64: 18446744073709551615}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	for args, message := range map[string]string{
+		"--int8=300":     "argument '--int8': unexpected argument (got '300', expected integer between -128 and 127)",
+		"--uint16=-1":    "argument '--uint16': unexpected argument (got '-1', expected unsigned integer)",
+		"--uint16=65536": "argument '--uint16': unexpected argument (got '65536', expected unsigned integer between 0 and 65535)",
+	} {
+		cfg = &config{}
+		if results, err = clap.Parse([]string{args}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+			t.Errorf("unexpected integer: %s", err)
+		} else if err.Error() != message {
+			t.Errorf("wanted: '%v', got '%v'", message, err)
+		}
+		t.Logf("t: %v\n", results)
+	}
+}
+
+func TestIntegerLiterals(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Mode   uint32 `clap:"--mode"`
+		Mask   uint8  `clap:"--mask"`
+		Count  int    `clap:"--count"`
+		Offset int    `clap:"--offset"`
+		Flags  []int  `clap:"--flags"`
+		Bits   [2]int `clap:"--bits"`
+		Day    int    `clap:"--day,base=10"`
+		Color  uint32 `clap:"--color,base=16,default=ff00ff"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--mode", "0o755", "--mask", "0xff", "--count", "1_000_000", "--offset", "-0x10",
+		"--flags", "0b101", "-0x1", "0755", "--bits=0x1,0x2", "--day", "08",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Mode: 0o755, Mask: 0xff, Count: 1_000_000, Offset: -0x10, Flags: []int{0b101, -0x1, 755},
+		Bits: [2]int{1, 2}, Day: 8, Color: 0xff00ff,
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"--day", "0x08"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected integer: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestLeadingZeros(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		N    int    `clap:"--n"`
+		Day  uint8  `clap:"--day"`
+		Port uint16 `clap:"--port"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--n", "010", "--day", "09", "--port", "08080"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{N: 10, Day: 9, Port: 8080}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestSliceAndArrayTypes(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Floats   []float64  `clap:"--floats"`
+		Float32s [2]float32 `clap:"--float32s"`
+		Uints    []uint16   `clap:"--uints"`
+		Int8s    [3]int8    `clap:"--int8s"`
+		Bools    []bool     `clap:"--bools"`
+		Uint64s  []uint64   `clap:"--uint64s,default=1|2"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--floats", "1.5", "-2.5", "--float32s", "0.5", "--uints=80,443", "--int8s", "-1", "0x7f",
+		"--bools", "true", "false", "1",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Floats: []float64{1.5, -2.5}, Float32s: [2]float32{0.5, 0}, Uints: []uint16{80, 443},
+		Int8s: [3]int8{-1, 127, 0}, Bools: []bool{true, false, true}, Uint64s: []uint64{1, 2},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"--uints", "80", "65536"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected uint16: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
@@ -0,0 +1,44 @@
+package clap
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// commandNames returns the sorted names of the commands of the struct
+func commandNames(fieldDescs map[string]*fieldDescription) []string {
+	var names []string
+	for name, desc := range fieldDescs {
+		if desc.Command != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unknownCommand returns an error if the struct has commands and arg is not
+// one of them, nor a flag
+func unknownCommand(arg string, fieldDescs map[string]*fieldDescription) error {
+	if strings.HasPrefix(arg, "-") {
+		return nil
+	}
+	names := commandNames(fieldDescs)
+	if len(names) == 0 {
+		return nil
+	}
+	return &ParseError{
+		Kind: ErrUnknownCommand, Name: arg, Value: arg,
+		details: "expected one of: " + strings.Join(names, ", "),
+	}
+}
+
+// commandToField parses the arguments following the command into the command
+// struct, and merges its results into the results of the parent
+func commandToField(desc *fieldDescription, field reflect.Value, results *Results, opts *options) error {
+	results.Commands = append(results.Commands, desc.Command)
+	commandResults, err := parse(desc.Args, field.Addr().Interface(), opts)
+	results.merge(commandResults)
+	return err
+}
@@ -0,0 +1,207 @@
+package clap_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fred1268/go-clap/clap"
+)
+
+type addParams struct {
+	Fetch bool     `clap:"--fetch,-f"`
+	Names []string `clap:"trailing"`
+}
+
+type removeParams struct {
+	Force bool `clap:"--force"`
+}
+
+type remoteParams struct {
+	Verbose bool         `clap:"--verbose,-v"`
+	Add     addParams    `clap:"command=add" help:"add a remote"`
+	Remove  removeParams `clap:"command=remove" help:"remove a remote"`
+}
+
+type runParams struct {
+	ForceRebuild bool     `clap:",-a"`
+	Binary       string   `clap:",-o,mandatory"`
+	Files        []string `clap:"trailing"`
+}
+
+type gitParams struct {
+	Directory string       `clap:"--directory,-C"`
+	Run       runParams    `clap:"command=run" help:"run the program"`
+	Remote    remoteParams `clap:"command=remote" help:"manage remotes"`
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+	cfg := &gitParams{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-C", "/tmp", "run", "-a", "-o", "bin", "main.go"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &gitParams{Directory: "/tmp", Run: runParams{ForceRebuild: true, Binary: "bin", Files: []string{"main.go"}}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if !reflect.DeepEqual(results.Commands, []string{"run"}) {
+		t.Errorf("wanted: '%v', got '%v'", []string{"run"}, results.Commands)
+	}
+}
+
+func TestNestedCommands(t *testing.T) {
+	t.Parallel()
+	cfg := &gitParams{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"remote", "-v", "add", "-f", "origin"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &gitParams{Remote: remoteParams{Verbose: true, Add: addParams{Fetch: true, Names: []string{"origin"}}}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if !reflect.DeepEqual(results.Commands, []string{"remote", "add"}) {
+		t.Errorf("wanted: '%v', got '%v'", []string{"remote", "add"}, results.Commands)
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	t.Parallel()
+	cfg := &gitParams{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-C", "/tmp", "remote", "rename"}, cfg); !errors.Is(err, clap.ErrUnknownCommand) {
+		t.Errorf("unexpected command: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if err != nil && !strings.Contains(err.Error(), "add, remove") {
+		t.Errorf("missing valid commands: %s", err)
+	}
+	cfg = &gitParams{}
+	if results, err = clap.Parse([]string{"run", "main.go"}, cfg); !errors.Is(err, clap.ErrMandatoryArgument) {
+		t.Errorf("unexpected command: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.Commands, []string{"run"}) || !reflect.DeepEqual(results.Mandatory, []string{"o"}) {
+		t.Errorf("unexpected results: %v", results)
+	}
+}
+
+func TestCommandHelp(t *testing.T) {
+	t.Parallel()
+	cfg := &gitParams{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"remote", "add", "--help"}, cfg, clap.WithHelp()); !errors.Is(err, clap.ErrHelp) {
+		t.Errorf("unexpected help: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.Commands, []string{"remote", "add"}) {
+		t.Errorf("wanted: '%v', got '%v'", []string{"remote", "add"}, results.Commands)
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	t.Parallel()
+	cfg := &gitParams{}
+	var sb strings.Builder
+	if err := clap.Usage(&sb, "git", cfg); err != nil {
+		t.Errorf("usage error: %s", err)
+	}
+	wanted := `Usage: git [options] <command>
+
+Commands:
+  run     run the program
+  remote  manage remotes
+
+Options:
+  -C, --directory <string>
+`
+	if sb.String() != wanted {
+		t.Errorf("wanted: '%v', got '%v'", wanted, sb.String())
+	}
+}
+
+func TestInvalidCommand(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Run int `clap:"command=run"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"run"}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected command: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestSliceBeforeCommand(t *testing.T) {
+	t.Parallel()
+	type serveParams struct {
+		Port int `clap:"--port"`
+	}
+	type config struct {
+		Tags  []string    `clap:"--tags"`
+		Serve serveParams `clap:"command=run"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--tags", "a", "b", "run", "--port", "1"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Tags: []string{"a", "b"}, Serve: serveParams{Port: 1}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if !reflect.DeepEqual(results.Commands, []string{"run"}) || len(results.Ignored) != 0 {
+		t.Errorf("unexpected results: %v", results)
+	}
+}
+
+func TestCommandsWithTrailing(t *testing.T) {
+	t.Parallel()
+	type buildParams struct {
+		Jobs int `clap:"--jobs,-j"`
+	}
+	type runParams struct {
+		Jobs    int         `clap:"--jobs,-j"`
+		Build   buildParams `clap:"command=build"`
+		Targets []string    `clap:"trailing"`
+	}
+	type config struct {
+		Run runParams `clap:"command=run"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"run", "-j", "3", "x", "y"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Run: runParams{Jobs: 3, Targets: []string{"x", "y"}}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"run", "build", "-j", "2"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted = &config{Run: runParams{Build: buildParams{Jobs: 2}}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if !reflect.DeepEqual(results.Commands, []string{"run", "build"}) {
+		t.Errorf("wanted: '%v', got '%v'", []string{"run", "build"}, results.Commands)
+	}
+}
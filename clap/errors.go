@@ -0,0 +1,88 @@
+package clap
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Represents an error found while parsing the struct tags or the
+command line. ParseError works with errors.Is, using its Kind:
+
+	errors.Is(err, clap.ErrMissingArgumentValue)
+
+and with errors.As to retrieve the details of the error:
+
+	var parseErr *clap.ParseError
+	if errors.As(err, &parseErr) {
+		fmt.Println(parseErr.Name, parseErr.Value)
+	}
+
+Kind: the kind of error, one of the Err* errors, for instance
+ErrUnexpectedArgument or ErrInvalidTag
+
+Name: the name of the parameter on the command line, like --port,
+or the name of the command for ErrUnknownCommand
+
+Field: the name of the struct field
+
+Value: the raw value, as found on the command line or in the tag
+
+Type: the expected type of the value, like integer
+
+Constraint: the constraint violated by the value, if any, like
+at least 1 or one of json, yaml
+
+Err: the underlying error, if any
+
+Suggestions: the names of the known parameters close to an unknown
+argument, for ErrUnknownArgument
+*/
+type ParseError struct {
+	Kind        error
+	Name        string
+	Field       string
+	Value       string
+	Type        string
+	Constraint  string
+	Err         error
+	Suggestions []string
+	details     string
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	switch {
+	case e.Kind == ErrUnknownCommand:
+		fmt.Fprintf(&sb, "command '%s': ", e.Name)
+	case e.Name != "":
+		fmt.Fprintf(&sb, "argument '%s': ", e.Name)
+	case e.Field != "":
+		fmt.Fprintf(&sb, "field '%s': ", e.Field)
+	}
+	sb.WriteString(e.Kind.Error())
+	switch {
+	case e.details != "":
+		fmt.Fprintf(&sb, " (%s)", e.details)
+	case e.expected() != "":
+		fmt.Fprintf(&sb, " (got '%s', expected %s)", e.Value, e.expected())
+	}
+	return sb.String()
+}
+
+// expected returns what the value should have been: the constraint it
+// violates, if any, or its type
+func (e *ParseError) expected() string {
+	if e.Constraint != "" {
+		return e.Constraint
+	}
+	return e.Type
+}
+
+// Unwrap returns the kind of the error and its underlying error, if any
+func (e *ParseError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
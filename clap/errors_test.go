@@ -0,0 +1,109 @@
+package clap_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fred1268/go-clap/clap"
+)
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port int `clap:"--port,-p"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--port", "http"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected port: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	var parseErr *clap.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("wanted: '*clap.ParseError', got '%T'", err)
+	}
+	wanted := clap.ParseError{Kind: clap.ErrUnexpectedArgument, Name: "--port", Field: "Port", Value: "http", Type: "integer"}
+	if parseErr.Kind != wanted.Kind || parseErr.Name != wanted.Name || parseErr.Field != wanted.Field ||
+		parseErr.Value != wanted.Value || parseErr.Type != wanted.Type || parseErr.Err == nil {
+		t.Errorf("wanted: '%+v', got '%+v'", wanted, *parseErr)
+	}
+	if err.Error() != "argument '--port': unexpected argument (got 'http', expected integer)" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestParseErrorKinds(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Name  string `clap:"--name,-n"`
+		Image string `clap:"--image,mandatory"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-n", "a", "-n", "b", "--name"}, cfg, clap.WithAllErrors()); err == nil {
+		t.Errorf("unexpected parsing success")
+	}
+	t.Logf("t: %v\n", results)
+	if !errors.Is(err, clap.ErrMissingArgumentValue) || !reflect.DeepEqual(results.Missing, []string{"--name"}) {
+		t.Errorf("wanted: missing value of '--name', got '%s' and '%v'", err, results.Missing)
+	}
+	var kinds []error
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var parseErr *clap.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("wanted: '*clap.ParseError', got '%T'", err)
+		}
+		kinds = append(kinds, parseErr.Kind)
+	}
+	wanted := []error{clap.ErrDuplicatedArgument, clap.ErrDuplicatedArgument, clap.ErrMandatoryArgument}
+	if len(kinds) != len(wanted) {
+		t.Fatalf("wanted: '%v', got '%v'", wanted, kinds)
+	}
+	for i := range kinds {
+		if kinds[i] != wanted[i] {
+			t.Errorf("wanted: '%v', got '%v'", wanted, kinds)
+		}
+	}
+}
+
+func TestInvalidTagParseError(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Test string `clap:"--test,-wrongtag"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--test", "list"}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected tag: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	var parseErr *clap.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Field != "Test" || parseErr.Value != "--test,-wrongtag" {
+		t.Errorf("unexpected error: %+v", parseErr)
+	}
+}
+
+func TestConstraintParseError(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port int `clap:"--port,-p,min=1"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--port", "0"}, cfg); !errors.Is(err, clap.ErrInvalidValue) {
+		t.Errorf("unexpected port: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	var parseErr *clap.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Type != "" || parseErr.Constraint != "at least 1" {
+		t.Errorf("unexpected error: %+v", parseErr)
+	}
+	if err.Error() != "argument '--port': invalid value (got '0', expected at least 1)" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
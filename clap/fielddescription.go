@@ -1,14 +1,59 @@
 package clap
 
-import "reflect"
+import (
+	"reflect"
+	"regexp"
+)
 
 type fieldDescription struct {
-	Field     int
-	ShortName string
-	LongName  string
-	Type      reflect.Type
-	Args      []string
-	Mandatory bool
-	Found     bool
-	Visited   bool
+	Field      []int
+	FieldName  string
+	ShortName  string
+	LongName   string
+	Type       reflect.Type
+	Pointer    bool
+	Help       string
+	Command    string
+	Env        string
+	Default    []string
+	Base       int
+	Layout     string
+	Repeat     string
+	Args       []string
+	FromEnv    bool
+	Mandatory  bool
+	Count      bool
+	Decrement  []string
+	Choices    []string
+	IgnoreCase bool
+	Min        reflect.Value
+	Max        reflect.Value
+	MinLen     int
+	MaxLen     int
+	Pattern    *regexp.Regexp
+	Found      bool
+	Visited    bool
+}
+
+// repeatPolicy returns the policy used when the parameter is repeated on the
+// command line: maps append their values by default, other types fail
+func (desc *fieldDescription) repeatPolicy() string {
+	switch {
+	case desc.Repeat != "":
+		return desc.Repeat
+	case kindOf(desc.Type) == reflect.Map:
+		return repeatAppend
+	}
+	return repeatError
+}
+
+// name returns the name of the parameter, as used on the command line
+func (desc *fieldDescription) name() string {
+	switch {
+	case desc.LongName != "":
+		return "--" + desc.LongName
+	case desc.ShortName != "":
+		return "-" + desc.ShortName
+	}
+	return trailing
 }
@@ -0,0 +1,63 @@
+package clap
+
+// Option represents an option changing the behavior of Parse
+type Option func(*options)
+
+type options struct {
+	help         bool
+	allErrors    bool
+	strict       bool
+	envSeparator string
+}
+
+/*
+Makes Parse return ErrHelp when -h or --help is present on the
+command line, unless the configuration struct uses these names
+for its own parameters
+*/
+func WithHelp() Option {
+	return func(o *options) {
+		o.help = true
+	}
+}
+
+/*
+Makes Parse go on after an error, in order to fill all the lists
+of Results and to return all the errors at once. The returned
+error wraps all of them, so that errors.Is works for each one
+*/
+func WithAllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
+
+/*
+Makes Parse return ErrUnknownArgument for any parameter that is not
+recognized, as well as for any stray value that is neither consumed
+by a parameter nor by the trailing field. Without this option, they
+are only reported in Results.Ignored
+*/
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+/*
+Sets the separator used to split the value of an environment variable
+into the values of a slice or an array. Defaults to ","
+*/
+func WithEnvSeparator(separator string) Option {
+	return func(o *options) {
+		o.envSeparator = separator
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{envSeparator: ","}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -4,7 +4,7 @@ package clap
 Represents the results of the command line parsing.
 
 Unexpected: contains unexpected parameters type (for instance expecting an integer
-and getting a string)
+and getting a string) or unknown commands
 
 Missing: contains non-boolean parameters with missing value(s)
 
@@ -15,13 +15,48 @@ Mandatory: contains mandatory (non optional) parameters that are not present in
 command line
 
 Duplicated: contains parameters that are duplicated on the command line
+
+InvalidChoices: contains parameters whose value is not one of their choices
+
+Invalid: contains parameters whose value does not satisfy their constraints,
+like min, max, minlen, maxlen or pattern
+
+Commands: contains the path of the selected command and subcommands, if any
+
+Suggestions: contains, for each ignored parameter, the names of the known
+parameters it is close to, the closest first (for instance --port for --prot)
 */
 type Results struct {
-	Unexpected []string
-	Missing    []string
-	Ignored    []string
-	Mandatory  []string
-	Duplicated []string
+	Unexpected     []string
+	Missing        []string
+	Ignored        []string
+	Mandatory      []string
+	Duplicated     []string
+	InvalidChoices []string
+	Invalid        []string
+	Commands       []string
+	Suggestions    map[string][]string
+}
+
+// merge appends the results of a command to the results of its parent
+func (r *Results) merge(other *Results) {
+	if other == nil {
+		return
+	}
+	r.Unexpected = append(r.Unexpected, other.Unexpected...)
+	r.Missing = append(r.Missing, other.Missing...)
+	r.Ignored = append(r.Ignored, other.Ignored...)
+	r.Mandatory = append(r.Mandatory, other.Mandatory...)
+	r.Duplicated = append(r.Duplicated, other.Duplicated...)
+	r.InvalidChoices = append(r.InvalidChoices, other.InvalidChoices...)
+	r.Invalid = append(r.Invalid, other.Invalid...)
+	r.Commands = append(r.Commands, other.Commands...)
+	for arg, names := range other.Suggestions {
+		if r.Suggestions == nil {
+			r.Suggestions = make(map[string][]string)
+		}
+		r.Suggestions[arg] = names
+	}
 }
 
 /*
@@ -34,9 +69,14 @@ Returns true if the parser returns any error. Errors are:
 - Mandatory parameters not present
 
 - Duplicated parameters
+
+- Parameters with invalid choices
+
+- Parameters with invalid values
 */
 func (r *Results) HasErrors() bool {
-	return len(r.Unexpected) != 0 || len(r.Missing) != 0 || len(r.Mandatory) != 0 || len(r.Duplicated) != 0
+	return len(r.Unexpected) != 0 || len(r.Missing) != 0 || len(r.Mandatory) != 0 || len(r.Duplicated) != 0 ||
+		len(r.InvalidChoices) != 0 || len(r.Invalid) != 0
 }
 
 /*
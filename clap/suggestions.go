@@ -0,0 +1,81 @@
+package clap
+
+import (
+	"sort"
+	"strings"
+)
+
+// editDistance returns the optimal string alignment distance between a and b,
+// which is the Levenshtein distance where swapping two adjacent characters
+// counts as a single edit
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// suggestions returns the names of the parameters close to the unknown argument,
+// the closest ones first. Only flags get suggestions: long flags are compared
+// with the long names, and short flags with the short names. A single char
+// is too short to be close to anything
+func suggestions(arg string, fieldDescs map[string]*fieldDescription) []string {
+	if !strings.HasPrefix(arg, "-") {
+		return nil
+	}
+	name, _, _ := splitArgument(arg)
+	long := strings.HasPrefix(name, "--")
+	name = strings.TrimLeft(name, "-")
+	if len(name) < 2 {
+		return nil
+	}
+	maxDistance := len(name) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	distances := make(map[string]int)
+	var names []string
+	for key := range fieldDescs {
+		if !strings.HasPrefix(key, "-") || strings.HasPrefix(key, "--") != long {
+			continue
+		}
+		if distance := editDistance(name, strings.TrimLeft(key, "-")); distance <= maxDistance {
+			distances[key] = distance
+			names = append(names, key)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if distances[names[i]] != distances[names[j]] {
+			return distances[names[i]] < distances[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
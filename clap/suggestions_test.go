@@ -0,0 +1,121 @@
+package clap_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/fred1268/go-clap/clap"
+)
+
+func TestSuggestions(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port    int    `clap:"--port,-p"`
+		Host    string `clap:"--host"`
+		Verbose bool   `clap:"--verbose,-v"`
+	}
+	cfg := &config{Port: 80}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--prot", "8080", "--hots=localhost", "--no-verbos", "--completely-unrelated",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := map[string][]string{
+		"--prot":           {"--port"},
+		"--hots=localhost": {"--host"},
+		"--no-verbos":      {"--no-verbose"},
+	}
+	if !reflect.DeepEqual(results.Suggestions, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, results.Suggestions)
+	}
+	if cfg.Port != 80 {
+		t.Errorf("wanted: '80', got '%v'", cfg.Port)
+	}
+}
+
+func TestNoSuggestions(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port    int  `clap:"--port,-p"`
+		Verbose bool `clap:"--verbose,-v"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"x", "2", "--x", "-x", "prot", "-p", "80"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if len(results.Suggestions) != 0 {
+		t.Errorf("unexpected suggestions: %v", results.Suggestions)
+	}
+	results, err = clap.Parse([]string{"x", "-p", "80"}, cfg, clap.WithStrict())
+	if !errors.Is(err, clap.ErrUnknownArgument) {
+		t.Errorf("unexpected stray argument: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if err.Error() != "argument 'x': unknown argument" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port  int      `clap:"--port,-p"`
+		Files []string `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	results, err = clap.Parse([]string{"--prot", "8080"}, cfg, clap.WithStrict())
+	if !errors.Is(err, clap.ErrUnknownArgument) {
+		t.Errorf("unexpected unknown argument: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	var parseErr *clap.ParseError
+	if !errors.As(err, &parseErr) || !reflect.DeepEqual(parseErr.Suggestions, []string{"--port"}) {
+		t.Errorf("unexpected error: %+v", parseErr)
+	}
+	if err.Error() != "argument '--prot': unknown argument (did you mean '--port'?)" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	cfg = &config{}
+	results, err = clap.Parse([]string{"stray", "-p", "8080", "file.txt"}, cfg, clap.WithStrict())
+	if !errors.Is(err, clap.ErrUnknownArgument) {
+		t.Errorf("unexpected stray argument: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"-p", "8080", "file.txt"}, cfg, clap.WithStrict()); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{Port: 8080, Files: []string{"file.txt"}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestStrictWithoutTrailing(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port int `clap:"--port,-p"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-p", "8080", "file.txt"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	results, err = clap.Parse([]string{"-p", "8080", "file.txt"}, cfg, clap.WithStrict())
+	if !errors.Is(err, clap.ErrUnknownArgument) {
+		t.Errorf("unexpected stray argument: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
@@ -4,116 +4,529 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
-var ErrInvalidTag = errors.New("invalid tag")
+var (
+	ErrInvalidTag     = errors.New("invalid tag")
+	ErrConflictingTag = errors.New("conflicting tag")
+)
 
 const (
-	trailing  string = "trailing"
-	mandatory string = "mandatory"
+	trailing   string = "trailing"
+	mandatory  string = "mandatory"
+	count      string = "count"
+	choices    string = "choices"
+	ignoreCase string = "ignorecase"
+	minimum    string = "min"
+	maximum    string = "max"
+	minLength  string = "minlen"
+	maxLength  string = "maxlen"
+	pattern    string = "pattern"
+	decrement  string = "decrement"
+	command    string = "command="
+	env        string = "env"
+	defaults   string = "default"
+	base       string = "base"
+	layout     string = "layout"
+	repeat     string = "repeat"
+	prefix     string = "prefix"
+	separator  string = "|"
 )
 
-func getTrailingFieldDescription(tags []string, field reflect.StructField) (*fieldDescription, error) {
+// Policies of the repeat option, telling how to handle a repeated argument
+const (
+	repeatError  string = "error"
+	repeatLast   string = "last"
+	repeatFirst  string = "first"
+	repeatAppend string = "append"
+)
+
+func getCommandFieldDescription(tags []string, field reflect.StructField) (*fieldDescription, error) {
 	fieldDesc := &fieldDescription{Type: field.Type}
 	if len(tags) != 1 {
-		return nil, fmt.Errorf("field '%s': %w (got '%s', expected 'trailing')", field.Name,
-			ErrInvalidTag, field.Tag.Get("clap"))
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "'command=name'",
+		}
+	}
+	fieldDesc.Command = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tags[0]), command))
+	if fieldDesc.Command == "" || fieldDesc.Command == trailing || strings.HasPrefix(fieldDesc.Command, "-") {
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a command name",
+		}
+	}
+	if fieldDesc.Type.Kind() != reflect.Struct {
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a struct",
+			details: "field should be a struct",
+		}
+	}
+	if _, err := computeFieldDescriptions(fieldDesc.Type); err != nil {
+		return nil, err
+	}
+	return fieldDesc, nil
+}
+
+// newFieldDescription returns the description of a field. Pointer fields are
+// described by the type they point to
+func newFieldDescription(field reflect.StructField) *fieldDescription {
+	if field.Type.Kind() == reflect.Pointer {
+		return &fieldDescription{Type: field.Type.Elem(), Pointer: true}
+	}
+	return &fieldDescription{Type: field.Type}
+}
+
+func getTrailingFieldDescription(tags []string, field reflect.StructField) (*fieldDescription, error) {
+	fieldDesc := newFieldDescription(field)
+	if len(tags) != 1 {
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "'trailing'",
+		}
 	}
 	if fieldDesc.Type.Kind() != reflect.Slice || fieldDesc.Type.Elem().Kind() != reflect.String {
-		return nil, fmt.Errorf("field '%s' should be a []string: %w", field.Name, ErrInvalidTag)
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a []string",
+			details: "field should be a []string",
+		}
 	}
 	return fieldDesc, nil
 }
 
+// isTagOption returns true if tag is an option rather than a name
+func isTagOption(tag string) bool {
+	tag = strings.Trim(tag, " ")
+	return tag == mandatory || tag == count || tag == ignoreCase || strings.Contains(tag, "=")
+}
+
+// parseDefault parses the default value of the field, using | to separate the
+// values of slices, arrays and maps, and makes sure it can be converted to the field type
+func parseDefault(value string, field reflect.StructField, fieldDesc *fieldDescription) error {
+	fieldDesc.Default = []string{value}
+	if kind := kindOf(fieldDesc.Type); kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map {
+		fieldDesc.Default = strings.Split(value, separator)
+	}
+	values, err := checkChoices(fieldDesc.Default, fieldDesc)
+	v := reflect.New(fieldDesc.Type).Elem()
+	if err == nil {
+		err = setValue(v, values, fieldDesc)
+	}
+	if err == nil {
+		err = checkConstraints(v, len(values), fieldDesc)
+	}
+	if err != nil {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: value, Type: err.Type, Constraint: err.Constraint,
+			details: fmt.Sprintf("invalid default value '%s', expected %s", value, err.expected()),
+		}
+	}
+	fieldDesc.Default = values
+	return nil
+}
+
+// parseBound converts the value of the min or max option to the type of the
+// values of the field
+func parseBound(value string, field reflect.StructField, fieldDesc *fieldDescription) (reflect.Value, error) {
+	v := reflect.New(scalarType(fieldDesc.Type)).Elem()
+	if err := setScalar(v, value, fieldDesc); err != nil {
+		return v, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: value, Type: err.Type,
+			details: fmt.Sprintf("invalid bound '%s', expected %s", value, err.Type),
+		}
+	}
+	return v, nil
+}
+
+// parseLength converts the value of the minlen or maxlen option, which must
+// be a positive length
+func parseLength(value string, field reflect.StructField) (int, error) {
+	val, err := strconv.Atoi(value)
+	if err != nil || val <= 0 {
+		return 0, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a positive length",
+		}
+	}
+	return val, nil
+}
+
+// splitTag splits the clap tag of the field on commas. A value starting with a
+// single quote, like pattern='^a,b$', goes on until the closing quote and can
+// contain commas, a quote inside it being written twice
+func splitTag(field reflect.StructField) ([]string, error) {
+	tagString := field.Tag.Get("clap")
+	var tags []string
+	start := 0
+	for i := 0; i < len(tagString); i++ {
+		switch {
+		case tagString[i] == ',':
+			tags = append(tags, tagString[start:i])
+			start = i + 1
+		case tagString[i] == '=' && !strings.Contains(tagString[start:i], "=") &&
+			strings.HasPrefix(tagString[i+1:], "'"):
+			end := i + 2
+			for ; end < len(tagString); end++ {
+				if tagString[end] == '\'' && strings.HasPrefix(tagString[end+1:], "'") {
+					end++
+				} else if tagString[end] == '\'' {
+					break
+				}
+			}
+			if end >= len(tagString) || !strings.HasPrefix(strings.TrimLeft(tagString[end+1:]+",", " "), ",") {
+				return nil, &ParseError{
+					Kind: ErrInvalidTag, Field: field.Name, Value: tagString,
+					details: fmt.Sprintf("invalid quoted value %s", tagString[i+1:]),
+				}
+			}
+			i = end
+		}
+	}
+	return append(tags, tagString[start:]), nil
+}
+
+// unquoteValue removes the quotes of a value quoted in the tag, like '^a,b$'
+func unquoteValue(value string) string {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return value
+	}
+	return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+}
+
+// invalidOption returns the error of an option which is unknown, misses its
+// value, or does not apply to the type of the field
+func invalidOption(tag string, field reflect.StructField, fieldDesc *fieldDescription) error {
+	key, value, hasValue := strings.Cut(tag, "=")
+	details := fmt.Sprintf("unknown option '%s'", key)
+	switch key {
+	case mandatory, count, ignoreCase:
+		details = fmt.Sprintf("option '%s' does not apply to type '%s'", key, fieldDesc.Type)
+		if hasValue {
+			details = fmt.Sprintf("option '%s' takes no value", key)
+		}
+	case env, defaults, base, layout, repeat, choices, decrement, minimum, maximum, minLength, maxLength, pattern:
+		details = fmt.Sprintf("option '%s' does not apply to type '%s'", key, fieldDesc.Type)
+		if value == "" {
+			details = fmt.Sprintf("option '%s' needs a value", key)
+		}
+	}
+	return &ParseError{
+		Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "'mandatory' or an option",
+		details: details,
+	}
+}
+
+// parseTagOptions parses the options following the names of the field,
+// like mandatory, count or env=NAME. The bounds and the default value are
+// parsed last, since they depend on the other options
+func parseTagOptions(tags []string, field reflect.StructField, fieldDesc *fieldDescription) error {
+	var defaultValue, minValue, maxValue string
+	for _, tag := range tags {
+		tag = strings.Trim(tag, " ")
+		key, value, _ := strings.Cut(tag, "=")
+		value = unquoteValue(value)
+		switch {
+		case tag == mandatory:
+			fieldDesc.Mandatory = true
+		case tag == count && isInteger(kindOf(fieldDesc.Type)):
+			fieldDesc.Count = true
+		case key == choices && value != "" && (kindOf(fieldDesc.Type) == reflect.String ||
+			elemKind(fieldDesc.Type) == reflect.String):
+			fieldDesc.Choices = strings.Split(value, separator)
+		case tag == ignoreCase:
+			fieldDesc.IgnoreCase = true
+		case key == minimum && value != "" && isBounded(fieldDesc.Type):
+			minValue = value
+		case key == maximum && value != "" && isBounded(fieldDesc.Type):
+			maxValue = value
+		case key == minLength && hasLength(fieldDesc.Type):
+			val, err := parseLength(value, field)
+			if err != nil {
+				return err
+			}
+			fieldDesc.MinLen = val
+		case key == maxLength && hasLength(fieldDesc.Type):
+			val, err := parseLength(value, field)
+			if err != nil {
+				return err
+			}
+			fieldDesc.MaxLen = val
+		case key == pattern && value != "" && isText(fieldDesc.Type):
+			re, err := regexp.Compile(value)
+			if err != nil {
+				return &ParseError{
+					Kind: ErrInvalidTag, Field: field.Name, Value: value, Type: "a regular expression", Err: err,
+				}
+			}
+			fieldDesc.Pattern = re
+		case key == decrement && value != "":
+			for _, name := range strings.Split(value, separator) {
+				name = strings.Trim(name, " -")
+				switch len(name) {
+				case 0:
+					return &ParseError{
+						Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a decrement name",
+					}
+				case 1:
+					fieldDesc.Decrement = append(fieldDesc.Decrement, "-"+name)
+				default:
+					fieldDesc.Decrement = append(fieldDesc.Decrement, "--"+name)
+				}
+			}
+		case key == env && value != "":
+			fieldDesc.Env = value
+		case key == defaults && value != "":
+			defaultValue = value
+		case key == base && (isInteger(kindOf(fieldDesc.Type)) || isInteger(elemKind(fieldDesc.Type))):
+			val, err := strconv.Atoi(value)
+			if err != nil || val < 2 || val > 36 {
+				return &ParseError{
+					Kind: ErrInvalidTag, Field: field.Name, Value: value, Constraint: "a base between 2 and 36",
+				}
+			}
+			fieldDesc.Base = val
+		case key == layout && value != "" && hasType(fieldDesc.Type, timeType):
+			fieldDesc.Layout = value
+		case key == repeat:
+			kind := kindOf(fieldDesc.Type)
+			switch {
+			case value == repeatError || value == repeatLast || value == repeatFirst:
+			case value == repeatAppend && (kind == reflect.Slice || kind == reflect.Map):
+			default:
+				return &ParseError{
+					Kind: ErrInvalidTag, Field: field.Name, Value: value,
+					Constraint: "'error', 'last', 'first' or 'append' (slices and maps only)",
+				}
+			}
+			fieldDesc.Repeat = value
+		default:
+			return invalidOption(tag, field, fieldDesc)
+		}
+	}
+	if len(fieldDesc.Decrement) != 0 && !fieldDesc.Count {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: "option 'decrement' needs option 'count'",
+		}
+	}
+	if len(fieldDesc.Decrement) != 0 && isInteger(kindOf(fieldDesc.Type)) && !isSigned(kindOf(fieldDesc.Type)) {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: "'decrement' needs a signed integer, since the count can be negative",
+		}
+	}
+	if fieldDesc.IgnoreCase && len(fieldDesc.Choices) == 0 {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: "option 'ignorecase' needs option 'choices'",
+		}
+	}
+	if fieldDesc.Mandatory && defaultValue != "" {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: "option 'mandatory' cannot be used with option 'default'",
+		}
+	}
+	if fieldDesc.Count && fieldDesc.Base != 0 {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: "option 'count' cannot be used with option 'base'",
+		}
+	}
+	var err error
+	if minValue != "" {
+		if fieldDesc.Min, err = parseBound(minValue, field, fieldDesc); err != nil {
+			return err
+		}
+	}
+	if maxValue != "" {
+		if fieldDesc.Max, err = parseBound(maxValue, field, fieldDesc); err != nil {
+			return err
+		}
+	}
+	if fieldDesc.Min.IsValid() && fieldDesc.Max.IsValid() && compareValues(fieldDesc.Min, fieldDesc.Max) > 0 {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: fmt.Sprintf("option 'min' (%v) is greater than option 'max' (%v)", fieldDesc.Min, fieldDesc.Max),
+		}
+	}
+	if fieldDesc.MinLen != 0 && fieldDesc.MaxLen != 0 && fieldDesc.MinLen > fieldDesc.MaxLen {
+		return &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"),
+			details: fmt.Sprintf("option 'minlen' (%d) is greater than option 'maxlen' (%d)", fieldDesc.MinLen,
+				fieldDesc.MaxLen),
+		}
+	}
+	if defaultValue != "" {
+		return parseDefault(defaultValue, field, fieldDesc)
+	}
+	return nil
+}
+
 func getShortNameFieldDescription(tags []string, field reflect.StructField) (*fieldDescription, error) {
-	fieldDesc := &fieldDescription{Type: field.Type}
-	if len(tags) > 3 || len(tags) < 2 {
-		return nil, fmt.Errorf("field '%s': %w (got '%s', expected two or three values)", field.Name,
-			ErrInvalidTag, field.Tag.Get("clap"))
+	fieldDesc := newFieldDescription(field)
+	if len(tags) < 2 {
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "at least two values",
+		}
 	}
 	fieldDesc.ShortName = strings.Trim(tags[1], " -")
 	if len(fieldDesc.ShortName) != 1 {
-		return nil, fmt.Errorf("field '%s': %w (got '%s', expected a single char value)", field.Name,
-			ErrInvalidTag, field.Tag.Get("clap"))
-	}
-	if len(tags) == 3 {
-		tag := strings.Trim(tags[2], " ")
-		if tag != mandatory {
-			return nil, fmt.Errorf("field '%s': %w (got '%s', expected '%s,mandatory')", field.Name,
-				ErrInvalidTag, field.Tag.Get("clap"), field.Name)
+		return nil, &ParseError{
+			Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a single char value",
 		}
-		fieldDesc.Mandatory = true
+	}
+	if err := parseTagOptions(tags[2:], field, fieldDesc); err != nil {
+		return nil, err
 	}
 	return fieldDesc, nil
 }
 
 func getLongNameFieldDescription(tags []string, field reflect.StructField) (*fieldDescription, error) {
-	fieldDesc := &fieldDescription{Type: field.Type}
+	fieldDesc := newFieldDescription(field)
 	if len(tags) > 1 {
-		tag := strings.Trim(tags[1], " -")
-		if tag == mandatory {
-			fieldDesc.Mandatory = true
-		} else {
-			fieldDesc.ShortName = tag
+		options := tags[1:]
+		if !isTagOption(tags[1]) {
+			fieldDesc.ShortName = strings.Trim(tags[1], " -")
 			if len(fieldDesc.ShortName) > 1 {
-				return nil, fmt.Errorf("field '%s': %w (got '%s', expected a single char value)", field.Name,
-					ErrInvalidTag, field.Tag.Get("clap"))
-			}
-			if len(tags) == 3 {
-				tag := strings.Trim(tags[2], " ")
-				if tag != mandatory {
-					return nil, fmt.Errorf("field '%s': %w (got '%s', expected '%s,mandatory')", field.Name,
-						ErrInvalidTag, field.Tag.Get("clap"), field.Name)
+				return nil, &ParseError{
+					Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a single char value",
 				}
-				fieldDesc.Mandatory = true
 			}
+			options = tags[2:]
+		}
+		if err := parseTagOptions(options, field, fieldDesc); err != nil {
+			return nil, err
 		}
 	}
 	return fieldDesc, nil
 }
 
+// addFieldDescription registers desc under key, like -x, --name or --no-name,
+// failing if a field, including desc itself, already uses the same key
+func addFieldDescription(fieldDescs map[string]*fieldDescription, key string, desc *fieldDescription,
+	field reflect.StructField,
+) error {
+	if other, ok := fieldDescs[key]; ok {
+		details := fmt.Sprintf("'%s' is used by fields '%s' and '%s'", key, other.FieldName, desc.FieldName)
+		if other == desc {
+			details = fmt.Sprintf("'%s' is used twice by field '%s'", key, desc.FieldName)
+		}
+		return &ParseError{
+			Kind: ErrConflictingTag, Field: desc.FieldName, Value: field.Tag.Get("clap"), details: details,
+		}
+	}
+	fieldDescs[key] = desc
+	return nil
+}
+
+// nestedPrefix returns true if the field is a struct whose fields are parameters,
+// along with the prefix of their long names: embedded structs without a tag
+// contribute their parameters directly, and structs tagged with the prefix
+// option, like `clap:"db,prefix"`, contribute parameters like --db-host
+func nestedPrefix(tags []string, field reflect.StructField) (string, bool, error) {
+	if len(tags) == 2 && strings.Trim(tags[1], " ") == prefix {
+		if field.Type.Kind() != reflect.Struct || isSupported(field.Type) {
+			return "", false, &ParseError{
+				Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a struct",
+				details: "field should be a struct",
+			}
+		}
+		if name := strings.Trim(tags[0], " -"); name != "" {
+			return name + "-", true, nil
+		}
+		return "", true, nil
+	}
+	if field.Anonymous && field.Tag.Get("clap") == "" && field.Type.Kind() == reflect.Struct &&
+		!isSupported(field.Type) {
+		return "", true, nil
+	}
+	return "", false, nil
+}
+
 func computeFieldDescriptions(t reflect.Type) (map[string]*fieldDescription, error) {
 	fieldDescs := make(map[string]*fieldDescription)
+	if err := addFieldDescriptions(fieldDescs, t, nil, "", ""); err != nil {
+		return nil, err
+	}
+	return fieldDescs, nil
+}
+
+// addFieldDescriptions adds the descriptions of the fields of t, a struct found
+// at index in the root struct, recursing into nested structs. Long names are
+// prefixed with namePrefix, and field names with path
+func addFieldDescriptions(fieldDescs map[string]*fieldDescription, t reflect.Type, index []int,
+	namePrefix, path string,
+) error {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
 		tagString := field.Tag.Get("clap")
-		if tagString != "" {
-			var err error
-			var fieldDesc *fieldDescription
-			tags := strings.Split(tagString, ",")
-			tag := strings.Trim(tags[0], " ")
-			switch tag {
-			case trailing:
-				fieldDesc, err = getTrailingFieldDescription(tags, field)
-				if err != nil {
-					return nil, err
-				}
-				fieldDescs[trailing] = fieldDesc
-			case "":
-				fieldDesc, err = getShortNameFieldDescription(tags, field)
-				if err != nil {
-					return nil, err
-				}
-				fieldDescs["-"+fieldDesc.ShortName] = fieldDesc
-			default:
-				fieldDesc, err = getLongNameFieldDescription(tags, field)
-				if err != nil {
-					return nil, err
-				}
-				fieldDesc.LongName = strings.Trim(tag, "-")
-				if fieldDesc.LongName != "" {
-					fieldDescs["--"+fieldDesc.LongName] = fieldDesc
-					if field.Type.Kind() == reflect.Bool {
-						fieldDescs["--no-"+fieldDesc.LongName] = fieldDesc
-					}
-				}
-				if fieldDesc.ShortName != "" {
-					fieldDescs["-"+fieldDesc.ShortName] = fieldDesc
+		tags, err := splitTag(field)
+		if err != nil {
+			return err
+		}
+		nested, ok, err := nestedPrefix(tags, field)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err = addFieldDescriptions(fieldDescs, field.Type, fieldIndex, namePrefix+nested,
+				path+field.Name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if tagString == "" {
+			continue
+		}
+		var fieldDesc *fieldDescription
+		var keys []string
+		tag := strings.Trim(tags[0], " ")
+		switch {
+		case strings.HasPrefix(tag, command):
+			if fieldDesc, err = getCommandFieldDescription(tags, field); err != nil {
+				return err
+			}
+			keys = append(keys, fieldDesc.Command)
+		case tag == trailing:
+			if fieldDesc, err = getTrailingFieldDescription(tags, field); err != nil {
+				return err
+			}
+			keys = append(keys, trailing)
+		case tag == "":
+			if fieldDesc, err = getShortNameFieldDescription(tags, field); err != nil {
+				return err
+			}
+			keys = append(keys, "-"+fieldDesc.ShortName)
+		default:
+			if fieldDesc, err = getLongNameFieldDescription(tags, field); err != nil {
+				return err
+			}
+			if name := strings.Trim(tag, "-"); name != "" {
+				fieldDesc.LongName = namePrefix + name
+				keys = append(keys, "--"+fieldDesc.LongName)
+				if kindOf(fieldDesc.Type) == reflect.Bool {
+					keys = append(keys, "--no-"+fieldDesc.LongName)
 				}
 			}
-			fieldDesc.Field = i
+			if fieldDesc.ShortName != "" {
+				keys = append(keys, "-"+fieldDesc.ShortName)
+			}
+		}
+		if fieldDesc.Command == "" && !isSupported(fieldDesc.Type) {
+			return &ParseError{
+				Kind: ErrInvalidTag, Field: field.Name, Value: field.Tag.Get("clap"), Type: "a supported type",
+				details: fmt.Sprintf("unsupported type '%s'", fieldDesc.Type),
+			}
+		}
+		fieldDesc.Field = fieldIndex
+		fieldDesc.FieldName = path + field.Name
+		fieldDesc.Help = field.Tag.Get("help")
+		for _, key := range append(keys, fieldDesc.Decrement...) {
+			if err = addFieldDescription(fieldDescs, key, fieldDesc, field); err != nil {
+				return err
+			}
 		}
 	}
-	return fieldDescs, nil
+	return nil
 }
@@ -1,7 +1,10 @@
 package clap_test
 
 import (
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/fred1268/go-clap/clap"
 )
@@ -77,3 +80,324 @@ func TestInvalidShortNameParameter(t *testing.T) {
 	}
 	t.Logf("t: %v\n", results)
 }
+
+func TestInvalidTagOption(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Field string `clap:"--string,env="`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--string", "hello"}, cfg); err == nil {
+		t.Errorf("unexpected valid option: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestInvalidDefault(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port int `clap:"--port,default=abc"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected valid default: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestMandatoryDefault(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port int `clap:"--port,mandatory,default=8080"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected mandatory default: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestInvalidBase(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Int    int    `clap:"--int,base=1"`
+		String string `clap:"--string,base=16"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected valid base: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestInvalidRepeat(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Strings [2]string `clap:"--strings,repeat=append"`
+		String  string    `clap:"--string,repeat=never"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected valid repeat: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestInvalidCount(t *testing.T) {
+	t.Parallel()
+	type stringCount struct {
+		String string `clap:"--string,count"`
+	}
+	type decrementOnly struct {
+		Int int `clap:"--int,decrement=quiet"`
+	}
+	type countBase struct {
+		Int int `clap:"--int,count,base=16"`
+	}
+	type unsignedDecrement struct {
+		Uint uint `clap:"--v,count,decrement=q"`
+	}
+	for _, parse := range []func() (*clap.Results, error){
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &stringCount{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &decrementOnly{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &countBase{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &unsignedDecrement{}) },
+	} {
+		results, err := parse()
+		if !errors.Is(err, clap.ErrInvalidTag) {
+			t.Errorf("unexpected valid count: %s", err)
+		}
+		t.Logf("t: %v\n", results)
+	}
+}
+
+func TestUnsupportedType(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Channels []chan int `clap:"--channels"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected supported type: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+type logging struct {
+	Level   string `clap:"--log-level,default=info"`
+	Verbose bool   `clap:"--verbose,-v"`
+}
+
+type TLS struct {
+	Cert string `clap:"--cert"`
+	Key  string `clap:"--key"`
+}
+
+type database struct {
+	Host    string `clap:"--host"`
+	Port    int    `clap:"--port,-P"`
+	Replica struct {
+		Host string `clap:"--host"`
+	} `clap:"replica,prefix"`
+}
+
+func TestNestedStructs(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		logging
+		TLS      `clap:"tls,prefix"`
+		Database database `clap:"db,prefix"`
+		Cache    TLS      `clap:"--cache,prefix"`
+		Ignored  database
+		Port     int      `clap:"--port,-p"`
+		Files    []string `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"-v", "--tls-cert", "cert.pem", "--db-host", "db.local", "-P", "5432", "--db-replica-host", "replica.local",
+		"--cache-key", "cache.pem", "-p", "8080", "file.txt",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		logging:  logging{Level: "info", Verbose: true},
+		TLS:      TLS{Cert: "cert.pem"},
+		Database: database{Host: "db.local", Port: 5432},
+		Cache:    TLS{Key: "cache.pem"},
+		Port:     8080,
+		Files:    []string{"file.txt"},
+	}
+	wanted.Database.Replica.Host = "replica.local"
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestNestedStructsCollision(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Database database `clap:"db,prefix"`
+		Cache    database `clap:"cache,prefix"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrConflictingTag) {
+		t.Errorf("unexpected valid short names: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if err.Error() != "field 'Cache.Port': conflicting tag ('-P' is used by fields 'Database.Port' and 'Cache.Port')" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	type embedded struct {
+		logging
+		Verbose bool `clap:"--verbose"`
+	}
+	if results, err = clap.Parse([]string{}, &embedded{}); !errors.Is(err, clap.ErrConflictingTag) {
+		t.Errorf("unexpected valid long names: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	type invalid struct {
+		Port int `clap:"port,prefix"`
+	}
+	if results, err = clap.Parse([]string{}, &invalid{}); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected valid prefix: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestConflictingTags(t *testing.T) {
+	t.Parallel()
+	type longNames struct {
+		Name  string `clap:"--name"`
+		Other string `clap:"--name"`
+	}
+	type shortNames struct {
+		Port    int  `clap:"--port,-p"`
+		Pretty  bool `clap:",-p"`
+		Verbose bool `clap:"--verbose"`
+	}
+	type negatedNames struct {
+		Color   bool `clap:"--color"`
+		NoColor bool `clap:"--no-color"`
+	}
+	type decrementNames struct {
+		Verbosity int  `clap:"--verbose,count,decrement=quiet|q"`
+		Quiet     bool `clap:"--quiet"`
+	}
+	type ownDecrementNames struct {
+		Verbosity int `clap:"verbose,v,count,decrement=v"`
+	}
+	type commandNames struct {
+		Run   struct{} `clap:"command=run"`
+		Again struct{} `clap:"command=run"`
+	}
+	for _, parse := range []func() (*clap.Results, error){
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &longNames{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &shortNames{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &negatedNames{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &decrementNames{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &ownDecrementNames{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &commandNames{}) },
+	} {
+		results, err := parse()
+		if !errors.Is(err, clap.ErrConflictingTag) {
+			t.Errorf("unexpected valid names: %s", err)
+		}
+		t.Logf("t: %v\n", results)
+	}
+	var parseErr *clap.ParseError
+	_, err := clap.Parse([]string{}, &negatedNames{})
+	if !errors.As(err, &parseErr) || parseErr.Field != "NoColor" {
+		t.Errorf("unexpected error: %+v", parseErr)
+	}
+	if err.Error() != "field 'NoColor': conflicting tag ('--no-color' is used by fields 'Color' and 'NoColor')" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	_, err = clap.Parse([]string{}, &ownDecrementNames{})
+	if err == nil || err.Error() != "field 'Verbosity': conflicting tag ('-v' is used twice by field 'Verbosity')" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestQuotedValues(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Code  string    `clap:"--code,pattern='^[a-z]{1,3}-[0-9]{2,}$',mandatory"`
+		Date  time.Time `clap:"--date,layout='Mon, 02 Jan 2006',default='Tue, 01 Oct 2024'"`
+		Greet string    `clap:"--greet,default='it''s, me'"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--code", "ab-123"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Code: "ab-123", Date: time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC), Greet: "it's, me",
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if results, err = clap.Parse([]string{"--code", "abcd-1"}, &config{}); !errors.Is(err, clap.ErrInvalidValue) {
+		t.Errorf("unexpected valid code: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{}, &config{}); !errors.Is(err, clap.ErrMandatoryArgument) {
+		t.Errorf("unexpected optional code: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestInvalidQuotedValues(t *testing.T) {
+	t.Parallel()
+	type unquotedComma struct {
+		Code string `clap:"--code,pattern=^a,b$,mandatory"`
+	}
+	type unterminatedQuote struct {
+		Code string `clap:"--code,pattern='^a,b$,mandatory"`
+	}
+	type textAfterQuote struct {
+		Code string `clap:"--code,pattern='^a'b$"`
+	}
+	tests := []struct {
+		parse   func() (*clap.Results, error)
+		message string
+	}{
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &unquotedComma{}) },
+			"field 'Code': invalid tag (unknown option 'b$')",
+		},
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &unterminatedQuote{}) },
+			"field 'Code': invalid tag (invalid quoted value '^a,b$,mandatory)",
+		},
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &textAfterQuote{}) },
+			"field 'Code': invalid tag (invalid quoted value '^a'b$)",
+		},
+	}
+	for _, test := range tests {
+		results, err := test.parse()
+		t.Logf("t: %v\n", results)
+		if !errors.Is(err, clap.ErrInvalidTag) || err.Error() != test.message {
+			t.Errorf("wanted: '%s', got '%s'", test.message, err)
+		}
+	}
+}
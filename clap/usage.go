@@ -0,0 +1,214 @@
+package clap
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	shortHelp string = "-h"
+	longHelp  string = "--help"
+)
+
+// isHelpRequested returns true if -h or --help is present before the end-of-options
+// marker and not used by the configuration struct
+func isHelpRequested(args []string, fieldDescs map[string]*fieldDescription) bool {
+	for _, arg := range args {
+		if arg == endOfOptions {
+			break
+		}
+		if desc, ok := fieldDescs[arg]; ok && desc.Command != "" {
+			break
+		}
+		if arg == shortHelp || arg == longHelp {
+			if _, ok := fieldDescs[arg]; !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lessIndex returns true if the field at index a comes before the field at
+// index b, both being paths from the root struct
+func lessIndex(a, b []int) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return a[k] < b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// sortedFieldDescriptions returns the field descriptions without duplicates,
+// in the order of the fields of the struct
+func sortedFieldDescriptions(fieldDescs map[string]*fieldDescription) []*fieldDescription {
+	var descs []*fieldDescription
+	seen := make(map[*fieldDescription]bool)
+	for _, desc := range fieldDescs {
+		if !seen[desc] {
+			seen[desc] = true
+			descs = append(descs, desc)
+		}
+	}
+	sort.Slice(descs, func(i, j int) bool {
+		return lessIndex(descs[i].Field, descs[j].Field)
+	})
+	return descs
+}
+
+// placeholder returns the name of the value expected by a parameter of type t
+func placeholder(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "<duration>"
+	case timeType:
+		return "<time>"
+	}
+	if isUnmarshaler(t) {
+		return "<value>"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "<int>"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "<uint>"
+	case reflect.Float32, reflect.Float64:
+		return "<float>"
+	case reflect.String:
+		return "<string>"
+	case reflect.Bool:
+		return "<bool>"
+	case reflect.Slice, reflect.Array:
+		return placeholder(t.Elem()) + "..."
+	case reflect.Map:
+		return placeholder(t.Key()) + "=" + placeholder(t.Elem()) + "..."
+	}
+	return ""
+}
+
+// names returns the names of the parameter, as shown in the usage
+func names(desc *fieldDescription) string {
+	var sb strings.Builder
+	if desc.ShortName != "" {
+		sb.WriteString("-" + desc.ShortName)
+		if desc.LongName != "" {
+			sb.WriteString(", ")
+		}
+	} else {
+		sb.WriteString("    ")
+	}
+	if desc.LongName != "" {
+		if kindOf(desc.Type) == reflect.Bool {
+			sb.WriteString("--[no-]" + desc.LongName)
+		} else {
+			sb.WriteString("--" + desc.LongName)
+		}
+	}
+	if kindOf(desc.Type) != reflect.Bool && !desc.Count {
+		sb.WriteString(" " + placeholder(desc.Type))
+	}
+	return sb.String()
+}
+
+// description returns the help of the parameter, followed by its mandatory
+// marker or its default value
+func description(desc *fieldDescription, reflectValue reflect.Value) string {
+	var details []string
+	if desc.Mandatory {
+		details = append(details, "mandatory")
+	}
+	if len(desc.Choices) != 0 {
+		details = append(details, "choices: "+strings.Join(desc.Choices, separator))
+	}
+	if desc.Env != "" {
+		details = append(details, "env: "+desc.Env)
+	}
+	if len(desc.Decrement) != 0 {
+		details = append(details, "decrement: "+strings.Join(desc.Decrement, ", "))
+	}
+	if len(desc.Default) != 0 {
+		details = append(details, "default: "+strings.Join(desc.Default, separator))
+	} else if field := reflect.Indirect(reflectValue.FieldByIndex(desc.Field)); field.IsValid() && field.CanInterface() &&
+		!field.IsZero() {
+		details = append(details, fmt.Sprintf("default: %v", field.Interface()))
+	}
+	if len(details) == 0 {
+		return desc.Help
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s)", desc.Help, strings.Join(details, ", ")))
+}
+
+func writeUsage(w io.Writer, name string, fieldDescs map[string]*fieldDescription, cfg any) error {
+	reflectValue := reflect.ValueOf(cfg).Elem()
+	var trailingDesc *fieldDescription
+	var descs, commands []*fieldDescription
+	for _, desc := range sortedFieldDescriptions(fieldDescs) {
+		switch {
+		case desc == fieldDescs[trailing]:
+			trailingDesc = desc
+		case desc.Command != "":
+			commands = append(commands, desc)
+		default:
+			descs = append(descs, desc)
+		}
+	}
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Usage: %s", name)
+	if len(descs) != 0 {
+		fmt.Fprint(tw, " [options]")
+	}
+	if len(commands) != 0 {
+		fmt.Fprint(tw, " <command>")
+	}
+	if trailingDesc != nil {
+		fmt.Fprint(tw, " [args...]")
+	}
+	fmt.Fprintln(tw)
+	if len(commands) != 0 {
+		fmt.Fprintf(tw, "\nCommands:\n")
+		for _, desc := range commands {
+			fmt.Fprintf(tw, "  %s\t%s\n", desc.Command, desc.Help)
+		}
+	}
+	if trailingDesc != nil && trailingDesc.Help != "" {
+		fmt.Fprintf(tw, "\nArguments:\n  args...\t%s\n", trailingDesc.Help)
+	}
+	if len(descs) != 0 {
+		fmt.Fprintf(tw, "\nOptions:\n")
+		for _, desc := range descs {
+			fmt.Fprintf(tw, "  %s\t%s\n", names(desc), description(desc, reflectValue))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	lines := strings.Split(sb.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n"))
+	return err
+}
+
+/*
+Writes the usage of the program to w, as described by the struct tags
+of cfg. Each parameter is listed with its long and short names, the
+type of the expected value, whether it is mandatory and its default
+value, which is taken from the content of cfg. A description can be
+added to each parameter with the help struct tag:
+
+	`clap:"--recursive,-R" help:"recurse into subdirectories"`
+*/
+func Usage[T any](w io.Writer, name string, cfg *T) error {
+	fieldDescs, err := computeFieldDescriptions(reflect.TypeOf(*cfg))
+	if err != nil {
+		return err
+	}
+	return writeUsage(w, name, fieldDescs, cfg)
+}
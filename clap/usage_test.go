@@ -0,0 +1,82 @@
+package clap_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fred1268/go-clap/clap"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Extensions  []string `clap:"--extensions,-e,mandatory" help:"extensions to look for"`
+		Recursive   bool     `clap:"--recursive,-r" help:"recurse into subdirectories"`
+		Size        int      `clap:"--size" help:"maximum size"`
+		Ratio       float64  `clap:",-R"`
+		Verbosity   int      `clap:"--verbose,-v,count,decrement=quiet|q" help:"increase verbosity"`
+		Format      string   `clap:"--format,default=json" help:"output format"`
+		Directories []string `clap:"trailing" help:"directories to scan"`
+	}
+	cfg := &config{Size: 10}
+	var sb strings.Builder
+	if err := clap.Usage(&sb, "find", cfg); err != nil {
+		t.Errorf("usage error: %s", err)
+	}
+	wanted := `Usage: find [options] [args...]
+
+Arguments:
+  args...  directories to scan
+
+Options:
+  -e, --extensions <string>...  extensions to look for (mandatory)
+  -r, --[no-]recursive          recurse into subdirectories
+      --size <int>              maximum size (default: 10)
+  -R <float>
+  -v, --verbose                 increase verbosity (decrement: --quiet, -q)
+      --format <string>         output format (default: json)
+`
+	if sb.String() != wanted {
+		t.Errorf("wanted: '%v', got '%v'", wanted, sb.String())
+	}
+}
+
+func TestHelp(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Recursive bool `clap:"--recursive,-r"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-r", "--help"}, cfg, clap.WithHelp()); !errors.Is(err, clap.ErrHelp) {
+		t.Errorf("unexpected help: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{"-r", "--", "-h"}, cfg, clap.WithHelp()); errors.Is(err, clap.ErrHelp) {
+		t.Errorf("unexpected help: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{"-h"}, cfg); errors.Is(err, clap.ErrHelp) {
+		t.Errorf("unexpected help: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestHelpOverridden(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Host string `clap:"--host,-h"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-h", "localhost"}, cfg, clap.WithHelp()); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if cfg.Host != "localhost" {
+		t.Errorf("wanted: 'localhost', got '%v'", cfg.Host)
+	}
+}
@@ -0,0 +1,164 @@
+package clap
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkChoices makes sure that the values are among the choices of the field,
+// if any, and returns them spelled as in the choices, which matters when the
+// case is ignored
+func checkChoices(args []string, desc *fieldDescription) ([]string, *ParseError) {
+	if len(desc.Choices) == 0 {
+		return args, nil
+	}
+	values := make([]string, 0, len(args))
+	for _, arg := range args {
+		found := false
+		for _, choice := range desc.Choices {
+			if arg == choice || (desc.IgnoreCase && strings.EqualFold(arg, choice)) {
+				values = append(values, choice)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, &ParseError{
+				Kind: ErrInvalidChoice, Value: arg, Constraint: "one of " + strings.Join(desc.Choices, ", "),
+			}
+		}
+	}
+	return values, nil
+}
+
+// scalarType returns the type of the elements of slices, arrays and maps,
+// or t for other types
+func scalarType(t reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return t
+}
+
+// isBounded returns true if the values of type t can have a minimum and
+// a maximum, which is the case of numbers and durations
+func isBounded(t reflect.Type) bool {
+	t = scalarType(t)
+	return t == durationType || isNumeric(kindOf(t))
+}
+
+// isText returns true if the values of type t are plain strings, which
+// can be matched against a pattern
+func isText(t reflect.Type) bool {
+	t = scalarType(t)
+	return t.Kind() == reflect.String && kindOf(t) == reflect.String
+}
+
+// hasLength returns true if the values of type t have a variable length:
+// strings have characters, slices and maps have values
+func hasLength(t reflect.Type) bool {
+	switch kindOf(t) {
+	case reflect.Slice, reflect.Map:
+		return true
+	}
+	return t.Kind() == reflect.String && kindOf(t) == reflect.String
+}
+
+// compareValues returns -1, 0 or 1 when a is respectively lower than, equal
+// to or greater than b, both being numbers of the same type
+func compareValues(a, b reflect.Value) int {
+	var less, greater bool
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less, greater = a.Int() < b.Int(), a.Int() > b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		less, greater = a.Uint() < b.Uint(), a.Uint() > b.Uint()
+	case reflect.Float32, reflect.Float64:
+		less, greater = a.Float() < b.Float(), a.Float() > b.Float()
+	}
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// checkLength makes sure that the length of v, in characters for strings
+// and in values for slices and maps, is within the bounds of the field
+func checkLength(v reflect.Value, desc *fieldDescription) *ParseError {
+	length, unit := v.Len(), "values"
+	if v.Kind() == reflect.String {
+		length, unit = utf8.RuneCountInString(v.String()), "characters"
+	}
+	switch {
+	case desc.MinLen != 0 && length < desc.MinLen:
+		return &ParseError{
+			Kind: ErrInvalidValue, Value: fmt.Sprint(v.Interface()),
+			Constraint: fmt.Sprintf("at least %d %s", desc.MinLen, unit),
+		}
+	case desc.MaxLen != 0 && length > desc.MaxLen:
+		return &ParseError{
+			Kind: ErrInvalidValue, Value: fmt.Sprint(v.Interface()),
+			Constraint: fmt.Sprintf("at most %d %s", desc.MaxLen, unit),
+		}
+	}
+	return nil
+}
+
+// checkScalar makes sure that v, a scalar value, is within the bounds of the
+// field and matches its pattern
+func checkScalar(v reflect.Value, desc *fieldDescription) *ParseError {
+	switch {
+	case desc.Min.IsValid() && compareValues(v, desc.Min) < 0:
+		return &ParseError{
+			Kind: ErrInvalidValue, Value: fmt.Sprint(v.Interface()), Constraint: fmt.Sprintf("at least %v", desc.Min),
+		}
+	case desc.Max.IsValid() && compareValues(v, desc.Max) > 0:
+		return &ParseError{
+			Kind: ErrInvalidValue, Value: fmt.Sprint(v.Interface()), Constraint: fmt.Sprintf("at most %v", desc.Max),
+		}
+	case desc.Pattern != nil && !desc.Pattern.MatchString(v.String()):
+		return &ParseError{
+			Kind: ErrInvalidValue, Value: v.String(), Constraint: fmt.Sprintf("a value matching '%s'", desc.Pattern),
+		}
+	}
+	return nil
+}
+
+// checkConstraints makes sure that v, the converted value of the field,
+// satisfies the constraints of the field. The values of slices, arrays
+// and maps are checked one by one, count being the number of values
+// given for arrays, whose other values are left untouched
+func checkConstraints(v reflect.Value, count int, desc *fieldDescription) *ParseError {
+	if desc.MinLen != 0 || desc.MaxLen != 0 {
+		if err := checkLength(v, desc); err != nil {
+			return err
+		}
+	}
+	if !desc.Min.IsValid() && !desc.Max.IsValid() && desc.Pattern == nil {
+		return nil
+	}
+	switch kindOf(v.Type()) {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len() && i < count; i++ {
+			if err := checkScalar(v.Index(i), desc); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := checkScalar(iter.Value(), desc); err != nil {
+				return err
+			}
+		}
+	default:
+		return checkScalar(v, desc)
+	}
+	return nil
+}
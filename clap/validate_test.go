@@ -0,0 +1,263 @@
+package clap_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fred1268/go-clap/clap"
+)
+
+func TestChoices(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Format  string   `clap:"--format,-f,choices=json|yaml|text,default=text"`
+		Level   *string  `clap:"--level,choices=DEBUG|INFO,ignorecase"`
+		Outputs []string `clap:"--outputs,choices=stdout|stderr|file"`
+		Mode    string   `clap:"--mode,choices=fast|safe"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"-f", "yaml", "--level", "debug", "--outputs", "stdout", "file"}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	level := "DEBUG"
+	wanted := &config{Format: "yaml", Level: &level, Outputs: []string{"stdout", "file"}}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	cfg = &config{}
+	if results, err = clap.Parse([]string{"--outputs", "stdout", "Stderr"}, cfg); !errors.Is(err, clap.ErrInvalidChoice) {
+		t.Errorf("unexpected choice: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if err.Error() != "argument '--outputs': invalid choice (got 'Stderr', expected one of stdout, stderr, file)" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	if !reflect.DeepEqual(results.InvalidChoices, []string{"--outputs"}) || !results.HasErrors() {
+		t.Errorf("wanted: '[--outputs]', got '%v'", results.InvalidChoices)
+	}
+	cfg = &config{}
+	results, err = clap.Parse([]string{"--format", "xml", "--mode", "slow"}, cfg, clap.WithAllErrors())
+	if !errors.Is(err, clap.ErrInvalidChoice) {
+		t.Errorf("unexpected choices: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.InvalidChoices, []string{"--format", "--mode"}) {
+		t.Errorf("wanted: '[--format --mode]', got '%v'", results.InvalidChoices)
+	}
+}
+
+func TestInvalidChoicesTag(t *testing.T) {
+	t.Parallel()
+	type invalidDefault struct {
+		Format string `clap:"--format,choices=json|yaml,default=xml"`
+	}
+	type intChoices struct {
+		Port int `clap:"--port,choices=80|443"`
+	}
+	type ignoreCaseOnly struct {
+		Format string `clap:"--format,ignorecase"`
+	}
+	for _, parse := range []func() (*clap.Results, error){
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &invalidDefault{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &intChoices{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &ignoreCaseOnly{}) },
+	} {
+		results, err := parse()
+		if !errors.Is(err, clap.ErrInvalidTag) {
+			t.Errorf("unexpected valid choices: %s", err)
+		}
+		t.Logf("t: %v\n", results)
+	}
+}
+
+func TestChoicesUsage(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Format string `clap:"--format,-f,choices=json|yaml|text,default=text" help:"output format"`
+	}
+	var sb strings.Builder
+	if err := clap.Usage(&sb, "report", &config{}); err != nil {
+		t.Errorf("usage error: %s", err)
+	}
+	wanted := `Usage: report [options]
+
+Options:
+  -f, --format <string>  output format (choices: json|yaml|text, default: text)
+`
+	if sb.String() != wanted {
+		t.Errorf("wanted: '%v', got '%v'", wanted, sb.String())
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port    int            `clap:"--port,-p,min=1,max=65535,default=8080"`
+		Ratio   float64        `clap:"--ratio,min=0,max=1"`
+		Timeout time.Duration  `clap:"--timeout,min=1s,max=1m"`
+		Name    string         `clap:"--name,minlen=3,maxlen=8,pattern=^[a-z]+$"`
+		Tags    []string       `clap:"--tags,maxlen=2,pattern=^[a-z]+$"`
+		Sizes   [3]uint        `clap:"--sizes,min=1"`
+		Weights map[string]int `clap:"--weight,max=10"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--ratio", "0.5", "--timeout", "30s", "--name", "héllo", "--tags", "a", "b", "--sizes", "1", "2",
+		"--weight", "a=10",
+	}, cfg); err == nil || !strings.Contains(err.Error(), "--name") {
+		t.Errorf("unexpected pattern: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	cfg = &config{}
+	if results, err = clap.Parse([]string{
+		"--ratio", "0.5", "--timeout", "30s", "--name", "hello", "--tags", "a", "b", "--sizes", "1", "2",
+		"--weight", "a=10",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Port: 8080, Ratio: 0.5, Timeout: 30 * time.Second, Name: "hello", Tags: []string{"a", "b"},
+		Sizes: [3]uint{1, 2}, Weights: map[string]int{"a": 10},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidValues(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Port    int            `clap:"--port,-p,min=1,max=65535"`
+		Timeout time.Duration  `clap:"--timeout,min=1s"`
+		Name    string         `clap:"--name,minlen=3,maxlen=8,pattern=^[a-z]+$"`
+		Tags    []string       `clap:"--tags,maxlen=2"`
+		Weights map[string]int `clap:"--weight,max=10"`
+	}
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"-p", "70000"}, "argument '--port': invalid value (got '70000', expected at most 65535)"},
+		{[]string{"-p", "0"}, "argument '--port': invalid value (got '0', expected at least 1)"},
+		{[]string{"--timeout", "10ms"}, "argument '--timeout': invalid value (got '10ms', expected at least 1s)"},
+		{[]string{"--name", "ab"}, "argument '--name': invalid value (got 'ab', expected at least 3 characters)"},
+		{[]string{"--name", "abcdefghi"}, "argument '--name': invalid value (got 'abcdefghi', expected at most 8 characters)"},
+		{[]string{"--name", "Hello"}, "argument '--name': invalid value (got 'Hello', expected a value matching '^[a-z]+$')"},
+		{[]string{"--tags", "a", "b", "c"}, "argument '--tags': invalid value (got '[a b c]', expected at most 2 values)"},
+		{[]string{"--weight", "a=11"}, "argument '--weight': invalid value (got '11', expected at most 10)"},
+	}
+	for _, test := range tests {
+		cfg := &config{}
+		results, err := clap.Parse(test.args, cfg)
+		if !errors.Is(err, clap.ErrInvalidValue) {
+			t.Errorf("unexpected valid value: %s", err)
+			continue
+		}
+		t.Logf("t: %v\n", results)
+		if err.Error() != test.message {
+			t.Errorf("wanted: '%s', got '%s'", test.message, err)
+		}
+		if len(results.Invalid) != 1 || !results.HasErrors() {
+			t.Errorf("unexpected results: %v", results)
+		}
+		if !reflect.DeepEqual(cfg, &config{}) {
+			t.Errorf("unexpected invalid value set: %v", cfg)
+		}
+	}
+}
+
+func TestInvalidConstraintsTag(t *testing.T) {
+	t.Parallel()
+	type stringMin struct {
+		Name string `clap:"--name,min=1"`
+	}
+	type invalidBound struct {
+		Port int `clap:"--port,max=high"`
+	}
+	type invalidLength struct {
+		Name string `clap:"--name,maxlen=0"`
+	}
+	type intPattern struct {
+		Port int `clap:"--port,pattern=^[0-9]+$"`
+	}
+	type invalidPattern struct {
+		Name string `clap:"--name,pattern=^[a-z"`
+	}
+	type invalidDefault struct {
+		Port int `clap:"--port,min=1,default=0"`
+	}
+	for _, parse := range []func() (*clap.Results, error){
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &stringMin{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &invalidBound{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &invalidLength{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &intPattern{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &invalidPattern{}) },
+		func() (*clap.Results, error) { return clap.Parse([]string{}, &invalidDefault{}) },
+	} {
+		results, err := parse()
+		if !errors.Is(err, clap.ErrInvalidTag) {
+			t.Errorf("unexpected valid constraints: %s", err)
+		}
+		t.Logf("t: %v\n", results)
+	}
+}
+
+func TestInvalidOptionMessages(t *testing.T) {
+	t.Parallel()
+	type arrayMinLen struct {
+		Sizes [3]int `clap:"--sizes,minlen=1"`
+	}
+	type invertedBounds struct {
+		Port int `clap:"--port,min=10,max=1"`
+	}
+	type invertedLengths struct {
+		Name string `clap:"--name,minlen=5,maxlen=2"`
+	}
+	type unknownOption struct {
+		Name string `clap:"--name,n,required"`
+	}
+	type missingValue struct {
+		Name string `clap:"--name,env="`
+	}
+	tests := []struct {
+		parse   func() (*clap.Results, error)
+		message string
+	}{
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &arrayMinLen{}) },
+			"field 'Sizes': invalid tag (option 'minlen' does not apply to type '[3]int')",
+		},
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &invertedBounds{}) },
+			"field 'Port': invalid tag (option 'min' (10) is greater than option 'max' (1))",
+		},
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &invertedLengths{}) },
+			"field 'Name': invalid tag (option 'minlen' (5) is greater than option 'maxlen' (2))",
+		},
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &unknownOption{}) },
+			"field 'Name': invalid tag (unknown option 'required')",
+		},
+		{
+			func() (*clap.Results, error) { return clap.Parse([]string{}, &missingValue{}) },
+			"field 'Name': invalid tag (option 'env' needs a value)",
+		},
+	}
+	for _, test := range tests {
+		results, err := test.parse()
+		t.Logf("t: %v\n", results)
+		if !errors.Is(err, clap.ErrInvalidTag) || err.Error() != test.message {
+			t.Errorf("wanted: '%s', got '%s'", test.message, err)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package clap
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// integerBase returns the base used to parse str. Without a base, integers
+// are decimal, unless they use the 0x, 0o or 0b prefixes or the _ separator
+// of the Go integer literals. A leading 0 alone does not mean octal
+func integerBase(str string, base int) int {
+	if base != 0 {
+		return base
+	}
+	digits := strings.TrimLeft(str, "+-")
+	if len(digits) > 1 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			return 0
+		}
+		return 10
+	}
+	if strings.Contains(digits, "_") {
+		return 0
+	}
+	return 10
+}
+
+// parseInt parses str as a signed integer of the given size, reporting the
+// valid range when str is out of range. A base of 0 accepts the Go integer
+// literals, like 0x1f, 0o755, 0b101 or 1_000, but parses 010 as 10
+func parseInt(str string, base, bitSize int) (int64, *ParseError) {
+	val, err := strconv.ParseInt(str, integerBase(str, base), bitSize)
+	if err == nil {
+		return val, nil
+	}
+	parseErr := &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: "integer", Err: err}
+	if errors.Is(err, strconv.ErrRange) {
+		parseErr.details = fmt.Sprintf("got '%s', expected integer between %d and %d", str,
+			int64(-1)<<(bitSize-1), int64(1)<<(bitSize-1)-1)
+	}
+	return 0, parseErr
+}
+
+// parseUint parses str as an unsigned integer of the given size, reporting
+// the valid range when str is out of range. A base of 0 accepts the Go
+// integer literals, like 0x1f, 0o755, 0b101 or 1_000, but parses 010 as 10
+func parseUint(str string, base, bitSize int) (uint64, *ParseError) {
+	val, err := strconv.ParseUint(str, integerBase(str, base), bitSize)
+	if err == nil {
+		return val, nil
+	}
+	parseErr := &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: "unsigned integer", Err: err}
+	if errors.Is(err, strconv.ErrRange) {
+		parseErr.details = fmt.Sprintf("got '%s', expected unsigned integer between 0 and %d", str,
+			uint64(1)<<bitSize-1)
+	}
+	return 0, parseErr
+}
+
+/*
+Represents a custom parameter type. Set is called with the value
+found on the command line, and String returns the current value.
+Fields, and elements of slices and arrays, whose pointer implements
+Value or encoding.TextUnmarshaler are filled using these interfaces
+rather than using their kind
+*/
+type Value interface {
+	Set(string) error
+	String() string
+}
+
+var (
+	valueType           = reflect.TypeOf((*Value)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// hasType returns true if t is target, or a slice, an array or a map of target
+func hasType(t, target reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if t != target {
+			return t.Elem() == target
+		}
+	}
+	return t == target
+}
+
+// isUnmarshaler returns true if the values of type t are set using the Value
+// or the encoding.TextUnmarshaler interface
+func isUnmarshaler(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	return ptr.Implements(valueType) || ptr.Implements(textUnmarshalerType)
+}
+
+// kindOf returns the kind of t, as seen by the parser: durations and types
+// set using an interface behave like strings, whatever their actual kind
+func kindOf(t reflect.Type) reflect.Kind {
+	if t == durationType || isUnmarshaler(t) {
+		return reflect.String
+	}
+	return t.Kind()
+}
+
+// isScalar returns true for the kinds of the values that can be converted
+// by setScalar
+func isScalar(kind reflect.Kind) bool {
+	return isNumeric(kind) || kind == reflect.String || kind == reflect.Bool
+}
+
+// isSupported returns true if the values of type t can be converted by setValue
+func isSupported(t reflect.Type) bool {
+	switch kindOf(t) {
+	case reflect.Slice, reflect.Array:
+		return isScalar(kindOf(t.Elem()))
+	case reflect.Map:
+		return isScalar(kindOf(t.Key())) && isScalar(kindOf(t.Elem()))
+	}
+	return isScalar(kindOf(t))
+}
+
+// setScalar converts str into the value of a scalar field, or of an element
+// of a slice or an array
+func setScalar(v reflect.Value, str string, desc *fieldDescription) *ParseError {
+	switch v.Type() {
+	case durationType:
+		val, err := time.ParseDuration(str)
+		if err != nil {
+			return &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: "duration", Err: err}
+		}
+		v.SetInt(int64(val))
+		return nil
+	case timeType:
+		format := desc.Layout
+		if format == "" {
+			format = time.RFC3339
+		}
+		val, err := time.Parse(format, str)
+		if err != nil {
+			return &ParseError{
+				Kind: ErrUnexpectedArgument, Value: str, Type: "time", Err: err,
+				details: fmt.Sprintf("got '%s', expected time with layout '%s'", str, format),
+			}
+		}
+		v.Set(reflect.ValueOf(val))
+		return nil
+	}
+	switch p := v.Addr().Interface().(type) {
+	case Value:
+		if err := p.Set(str); err != nil {
+			return &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: v.Type().String(), Err: err}
+		}
+		return nil
+	case encoding.TextUnmarshaler:
+		if err := p.UnmarshalText([]byte(str)); err != nil {
+			return &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: v.Type().String(), Err: err}
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := parseInt(str, desc.Base, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := parseUint(str, desc.Base, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(str, v.Type().Bits())
+		if err != nil {
+			return &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: "float", Err: err}
+		}
+		v.SetFloat(val)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return &ParseError{Kind: ErrUnexpectedArgument, Value: str, Type: "boolean", Err: err}
+		}
+		v.SetBool(val)
+	}
+	return nil
+}
+
+// setValue converts the arguments into the value of the field, as described
+// by desc. Scalar fields only use the first argument, and maps use arguments
+// formatted as key=value, each key being unique. The returned error has
+// no name nor field, which are to be filled by the caller
+func setValue(field reflect.Value, args []string, desc *fieldDescription) *ParseError {
+	switch kindOf(field.Type()) {
+	case reflect.Slice:
+		v := reflect.MakeSlice(field.Type(), len(args), len(args))
+		for i, arg := range args {
+			if err := setScalar(v.Index(i), arg, desc); err != nil {
+				return err
+			}
+		}
+		field.Set(v)
+	case reflect.Array:
+		if len(args) > field.Len() {
+			return &ParseError{
+				Kind: ErrUnexpectedArgument, Value: strings.Join(args, " "),
+				details: fmt.Sprintf("got %d values, expected at most %d", len(args), field.Len()),
+			}
+		}
+		v := reflect.New(field.Type()).Elem()
+		for i, arg := range args {
+			if err := setScalar(v.Index(i), arg, desc); err != nil {
+				return err
+			}
+		}
+		field.Set(v)
+	case reflect.Map:
+		v := reflect.MakeMapWithSize(field.Type(), len(args))
+		for _, arg := range args {
+			k, val, ok := strings.Cut(arg, "=")
+			if !ok {
+				return &ParseError{Kind: ErrUnexpectedArgument, Value: arg, Type: "key=value"}
+			}
+			key := reflect.New(field.Type().Key()).Elem()
+			if err := setScalar(key, k, desc); err != nil {
+				return err
+			}
+			if v.MapIndex(key).IsValid() {
+				return &ParseError{
+					Kind: ErrDuplicatedArgument, Value: arg, details: fmt.Sprintf("duplicated key '%s'", k),
+				}
+			}
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setScalar(elem, val, desc); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+		field.Set(v)
+	default:
+		return setScalar(field, args[0], desc)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package clap_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fred1268/go-clap/clap"
+)
+
+type level int
+
+func (l *level) Set(value string) error {
+	switch value {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level '%s'", value)
+	}
+	return nil
+}
+
+func (l level) String() string {
+	return [...]string{"debug", "info", "error"}[l]
+}
+
+func TestUnmarshalers(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Addr    netip.Addr    `clap:"--addr"`
+		IP      net.IP        `clap:"--ip"`
+		Level   level         `clap:"--level,-l,default=info"`
+		Levels  []level       `clap:"--levels"`
+		Addrs   [2]netip.Addr `clap:"--addrs"`
+		Prefix  netip.Prefix  `clap:"--prefix"`
+		Verbose bool          `clap:"--verbose,-v"`
+		Files   []string      `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--addr", "127.0.0.1", "--ip", "::1", "--levels", "debug", "error", "--addrs=10.0.0.1,10.0.0.2",
+		"--prefix", "10.0.0.0/8", "-v", "file.txt",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Addr: netip.MustParseAddr("127.0.0.1"), IP: net.ParseIP("::1"), Level: 1, Levels: []level{0, 2},
+		Addrs:  [2]netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")},
+		Prefix: netip.MustParsePrefix("10.0.0.0/8"), Verbose: true, Files: []string{"file.txt"},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidUnmarshalers(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Addr  netip.Addr `clap:"--addr"`
+		Level level      `clap:"--level"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--addr", "localhost"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected address: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	var parseErr *clap.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Type != "netip.Addr" || parseErr.Err == nil {
+		t.Errorf("unexpected error: %+v", parseErr)
+	}
+	if results, err = clap.Parse([]string{"--level", "trace"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected level: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestInvalidUnmarshalerDefault(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Level level `clap:"--level,default=trace"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{}, cfg); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected default level: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestTimes(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Timeout  time.Duration   `clap:"--timeout,-t,default=30s"`
+		Offset   time.Duration   `clap:"--offset"`
+		Retries  []time.Duration `clap:"--retries"`
+		Since    time.Time       `clap:"--since"`
+		Until    time.Time       `clap:"--until,layout=2006-01-02"`
+		Holidays [2]time.Time    `clap:"--holidays,layout=2006-01-02,default=2024-12-25|2025-01-01"`
+		Files    []string        `clap:"trailing"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--offset", "-1h30m", "--retries", "1s", "2s", "500ms", "--since=2024-03-01T10:00:00Z",
+		"--until", "2024-04-01", "file.txt",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Timeout: 30 * time.Second, Offset: -90 * time.Minute,
+		Retries: []time.Duration{time.Second, 2 * time.Second, 500 * time.Millisecond},
+		Since:   time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		Until:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Holidays: [2]time.Time{
+			time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Files: []string{"file.txt"},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidTimes(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Timeout time.Duration `clap:"--timeout"`
+		Until   time.Time     `clap:"--until,layout=2006-01-02"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--timeout", "30"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected duration: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{"--until", "2024-04-01T10:00:00Z"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected time: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if err.Error() != "argument '--until': unexpected argument (got '2024-04-01T10:00:00Z', expected time with layout '2006-01-02')" {
+		t.Errorf("unexpected message: %s", err)
+	}
+	type invalid struct {
+		Timeout time.Duration `clap:"--timeout,layout=2006-01-02"`
+	}
+	if results, err = clap.Parse([]string{}, &invalid{}); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected layout: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
+
+func TestMaps(t *testing.T) {
+	t.Setenv("CLAP_TEST_HEADERS", "Accept=text/plain,X-Id=1")
+	type config struct {
+		Labels  map[string]string `clap:"--label,-l"`
+		Weights map[string]int    `clap:"--weight"`
+		Ports   map[int]bool      `clap:"--port,default=80=true|443=false"`
+		Headers map[string]string `clap:"--header,env=CLAP_TEST_HEADERS"`
+		Verbose bool              `clap:"--verbose,-v"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--label", "env=prod", "-v", "-l", "team=core", "zone=eu=west", "--weight=a=1,b=-2",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	wanted := &config{
+		Labels:  map[string]string{"env": "prod", "team": "core", "zone": "eu=west"},
+		Weights: map[string]int{"a": 1, "b": -2},
+		Ports:   map[int]bool{80: true, 443: false},
+		Headers: map[string]string{"Accept": "text/plain", "X-Id": "1"},
+		Verbose: true,
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+}
+
+func TestInvalidMaps(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Labels  map[string]string `clap:"--label,-l"`
+		Weights map[string]int    `clap:"--weight"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--label", "env"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected malformed pair: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if results, err = clap.Parse([]string{"--weight", "a=heavy"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected map value: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	results, err = clap.Parse([]string{"--label", "env=prod", "-l", "env=dev"}, cfg)
+	if !errors.Is(err, clap.ErrDuplicatedArgument) {
+		t.Errorf("unexpected duplicated key: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if !reflect.DeepEqual(results.Duplicated, []string{"--label"}) {
+		t.Errorf("wanted: '[--label]', got '%v'", results.Duplicated)
+	}
+	if err.Error() != "argument '--label': duplicated argument (duplicated key 'env')" {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestPointers(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Retries  *int               `clap:"--retries,-r"`
+		Name     *string            `clap:"--name"`
+		Tags     *[]string          `clap:"--tags"`
+		Timeout  *time.Duration     `clap:"--timeout"`
+		Verbose  *bool              `clap:"--verbose,-v"`
+		Labels   *map[string]string `clap:"--label"`
+		Level    *level             `clap:"--level,default=error"`
+		Override *int               `clap:"--override"`
+		Files    *[]string          `clap:"trailing"`
+	}
+	override := 7
+	cfg := &config{Override: &override}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{
+		"--label", "env=prod", "--retries", "0", "--tags", "a", "b", "--timeout=1m", "--no-verbose", "file.txt",
+	}, cfg); err != nil {
+		t.Errorf("parsing error: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	retries, timeout, verbose, lvl := 0, time.Minute, false, level(2)
+	wanted := &config{
+		Retries: &retries, Tags: &[]string{"a", "b"}, Timeout: &timeout, Verbose: &verbose,
+		Labels: &map[string]string{"env": "prod"}, Level: &lvl, Override: &override, Files: &[]string{"file.txt"},
+	}
+	if !reflect.DeepEqual(cfg, wanted) {
+		t.Errorf("wanted: '%v', got '%v'", wanted, cfg)
+	}
+	if cfg.Name != nil {
+		t.Errorf("wanted: 'nil', got '%v'", *cfg.Name)
+	}
+}
+
+func TestInvalidPointers(t *testing.T) {
+	t.Parallel()
+	type config struct {
+		Retries *int `clap:"--retries"`
+	}
+	cfg := &config{}
+	var err error
+	var results *clap.Results
+	if results, err = clap.Parse([]string{"--retries", "many"}, cfg); !errors.Is(err, clap.ErrUnexpectedArgument) {
+		t.Errorf("unexpected pointer value: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+	if cfg.Retries != nil {
+		t.Errorf("wanted: 'nil', got '%v'", *cfg.Retries)
+	}
+	type invalid struct {
+		Retries **int `clap:"--retries"`
+	}
+	if results, err = clap.Parse([]string{}, &invalid{}); !errors.Is(err, clap.ErrInvalidTag) {
+		t.Errorf("unexpected supported pointer: %s", err)
+	}
+	t.Logf("t: %v\n", results)
+}
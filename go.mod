@@ -1,3 +1,3 @@
 module github.com/fred1268/go-clap
 
-go 1.18
+go 1.20